@@ -0,0 +1,31 @@
+// Command swarm-mobile-daemon runs the node of Swarm Mobile without GUI, e.g. on a server or in CI.
+// It does not link Fyne, so it builds without the graphics libraries.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/Solar-Punk-Ltd/swarm-mobile/internal/daemon"
+)
+
+func main() {
+	daemonFlags := daemon.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+
+	config, err := daemonFlags.Config()
+	if err != nil {
+		tidyUp(err.Error())
+		os.Exit(1)
+	}
+	if err := daemon.Run(config); err != nil {
+		tidyUp(err.Error())
+		os.Exit(1)
+	}
+	tidyUp("Interrupted")
+}
+
+func tidyUp(msg string) {
+	fmt.Printf("Swarm Mobile Exited: %s\n", msg)
+}
@@ -0,0 +1,144 @@
+package applock
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	fileName = "applock.json"
+	saltLen  = 16
+	keyLen   = 32
+	scryptN  = 1 << 15
+	scryptR  = 8
+	scryptP  = 1
+	// MinPINLength is the minimal number of digits of a PIN.
+	MinPINLength = 4
+)
+
+var (
+	ErrWrongPIN   = errors.New("wrong PIN")
+	ErrInvalidPIN = fmt.Errorf("PIN must have at least %d digits", MinPINLength)
+)
+
+// pinHash is the scrypt hash of a PIN as saved in the data directory.
+type pinHash struct {
+	Salt []byte `json:"salt"`
+	Hash []byte `json:"hash"`
+	N    int    `json:"n"`
+	R    int    `json:"r"`
+	P    int    `json:"p"`
+}
+
+// Lock keeps the hash of the app lock PIN in a file of the data directory.
+type Lock struct {
+	mu   sync.Mutex
+	path string
+	pin  *pinHash
+}
+
+// Open loads the PIN hash from dir. The lock is disabled if no PIN is set.
+func Open(dir string) (*Lock, error) {
+	l := &Lock{path: filepath.Join(dir, fileName)}
+	data, err := os.ReadFile(l.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return l, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open app lock: %w", err)
+	}
+	pin := &pinHash{}
+	if err := json.Unmarshal(data, pin); err != nil {
+		return nil, fmt.Errorf("open app lock: %w", err)
+	}
+	l.pin = pin
+	return l, nil
+}
+
+// Enabled reports whether a PIN is set.
+func (l *Lock) Enabled() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.pin != nil
+}
+
+// Set hashes the PIN and saves it, replacing the previous one.
+func (l *Lock) Set(pin string) error {
+	if !validPIN(pin) {
+		return ErrInvalidPIN
+	}
+	salt := make([]byte, saltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+	hash, err := scrypt.Key([]byte(pin), salt, scryptN, scryptR, scryptP, keyLen)
+	if err != nil {
+		return err
+	}
+	p := &pinHash{Salt: salt, Hash: hash, N: scryptN, R: scryptR, P: scryptP}
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	tmp := l.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, l.path); err != nil {
+		return err
+	}
+	l.pin = p
+	return nil
+}
+
+// Verify checks the PIN against the saved hash. It returns ErrWrongPIN if it does not match.
+func (l *Lock) Verify(pin string) error {
+	l.mu.Lock()
+	p := l.pin
+	l.mu.Unlock()
+	if p == nil {
+		return nil
+	}
+	hash, err := scrypt.Key([]byte(pin), p.Salt, p.N, p.R, p.P, len(p.Hash))
+	if err != nil {
+		return err
+	}
+	if subtle.ConstantTimeCompare(hash, p.Hash) != 1 {
+		return ErrWrongPIN
+	}
+	return nil
+}
+
+// Remove deletes the PIN, disabling the lock.
+func (l *Lock) Remove() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := os.Remove(l.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	l.pin = nil
+	return nil
+}
+
+func validPIN(pin string) bool {
+	if len(pin) < MinPINLength {
+		return false
+	}
+	for _, c := range pin {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,94 @@
+package applock
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func openTestLock(t *testing.T, dir string) *Lock {
+	t.Helper()
+	l, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return l
+}
+
+func TestLock(t *testing.T) {
+	dir := t.TempDir()
+	l := openTestLock(t, dir)
+	if l.Enabled() {
+		t.Fatal("the lock is enabled without a PIN")
+	}
+	if err := l.Verify("0000"); err != nil {
+		t.Fatalf("a disabled lock refuses a PIN: %v", err)
+	}
+
+	if err := l.Set("1234"); err != nil {
+		t.Fatal(err)
+	}
+	if !l.Enabled() {
+		t.Fatal("the lock is disabled after setting a PIN")
+	}
+	if err := l.Verify("1234"); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Verify("1235"); !errors.Is(err, ErrWrongPIN) {
+		t.Fatalf("got error %v, want %v", err, ErrWrongPIN)
+	}
+
+	// the PIN is kept in the data directory
+	reopened := openTestLock(t, dir)
+	if !reopened.Enabled() {
+		t.Fatal("the PIN is lost after reopening")
+	}
+	if err := reopened.Verify("1234"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := reopened.Set("98765"); err != nil {
+		t.Fatal(err)
+	}
+	if err := reopened.Verify("1234"); !errors.Is(err, ErrWrongPIN) {
+		t.Fatalf("the replaced PIN is accepted: %v", err)
+	}
+
+	if err := reopened.Remove(); err != nil {
+		t.Fatal(err)
+	}
+	if reopened.Enabled() {
+		t.Fatal("the lock is enabled after removing the PIN")
+	}
+	if err := reopened.Remove(); err != nil {
+		t.Fatalf("removing a removed PIN: %v", err)
+	}
+	if openTestLock(t, dir).Enabled() {
+		t.Fatal("the removed PIN is loaded again")
+	}
+}
+
+func TestSetInvalidPIN(t *testing.T) {
+	for _, pin := range []string{"", "123", "12a4", "12 34", "١٢٣٤"} {
+		t.Run(pin, func(t *testing.T) {
+			l := openTestLock(t, t.TempDir())
+			if err := l.Set(pin); !errors.Is(err, ErrInvalidPIN) {
+				t.Fatalf("got error %v, want %v", err, ErrInvalidPIN)
+			}
+			if l.Enabled() {
+				t.Fatal("the lock is enabled by an invalid PIN")
+			}
+		})
+	}
+}
+
+func TestOpenCorrupted(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, fileName), []byte("{"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open(dir); err == nil {
+		t.Fatal("expected an error for a corrupted lock file")
+	}
+}
@@ -0,0 +1,184 @@
+package daemon
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/Solar-Punk-Ltd/swarm-mobile/internal/node"
+)
+
+const envPrefix = "SWARM_MOBILE_"
+
+// Config holds the node settings of the headless mode. Values are resolved
+// in the order: command line flags, environment variables, config file, defaults.
+type Config struct {
+	DataDir        string `json:"dataDir"`
+	Password       string `json:"password"`
+	WelcomeMessage string `json:"welcomeMessage"`
+	NATAddress     string `json:"natAddress"`
+	RPCEndpoint    string `json:"rpcEndpoint"`
+	SwapEnable     bool   `json:"swapEnable"`
+	// Network is mainnet, testnet or custom, mainnet if empty. NetworkID and Bootnodes set the custom network.
+	Network   string   `json:"network"`
+	NetworkID uint64   `json:"networkID"`
+	Bootnodes []string `json:"bootnodes"`
+}
+
+// Flags binds the headless settings to a flag set.
+type Flags struct {
+	fs        *flag.FlagSet
+	config    string
+	bootnodes string
+	values    Config
+}
+
+func RegisterFlags(fs *flag.FlagSet) *Flags {
+	f := &Flags{fs: fs}
+	fs.StringVar(&f.config, "config", "", "path of a JSON config file for the headless mode")
+	fs.StringVar(&f.values.DataDir, "data-dir", "", "data directory of the node (env "+envPrefix+"DATA_DIR)")
+	fs.StringVar(&f.values.Password, "password", "", "password of the node keystore (env "+envPrefix+"PASSWORD)")
+	fs.StringVar(&f.values.WelcomeMessage, "welcome-message", "", "welcome message of the node (env "+envPrefix+"WELCOME_MESSAGE)")
+	fs.StringVar(&f.values.NATAddress, "nat-address", "", "NAT address of the node (env "+envPrefix+"NAT_ADDRESS)")
+	fs.StringVar(&f.values.RPCEndpoint, "rpc-endpoint", "", "blockchain RPC endpoint, required in light mode (env "+envPrefix+"RPC_ENDPOINT)")
+	fs.BoolVar(&f.values.SwapEnable, "swap-enable", false, "enable SWAP and run in light mode (env "+envPrefix+"SWAP_ENABLE)")
+	fs.StringVar(&f.values.Network, "network", "", "swarm network to join: mainnet, testnet or custom (env "+envPrefix+"NETWORK)")
+	fs.Uint64Var(&f.values.NetworkID, "network-id", 0, "network ID of the custom network (env "+envPrefix+"NETWORK_ID)")
+	fs.StringVar(&f.bootnodes, "bootnodes", "", "comma separated bootnode multiaddrs of the custom network (env "+envPrefix+"BOOTNODES)")
+	return f
+}
+
+// Config resolves the settings once the flag set has been parsed.
+func (f *Flags) Config() (*Config, error) {
+	c := &Config{}
+	if f.config != "" {
+		data, err := os.ReadFile(f.config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config file: %w", err)
+		}
+		if err := json.Unmarshal(data, c); err != nil {
+			return nil, fmt.Errorf("failed to parse config file: %w", err)
+		}
+	}
+
+	if err := c.loadEnv(); err != nil {
+		return nil, err
+	}
+
+	f.fs.Visit(func(fl *flag.Flag) {
+		switch fl.Name {
+		case "data-dir":
+			c.DataDir = f.values.DataDir
+		case "password":
+			c.Password = f.values.Password
+		case "welcome-message":
+			c.WelcomeMessage = f.values.WelcomeMessage
+		case "nat-address":
+			c.NATAddress = f.values.NATAddress
+		case "rpc-endpoint":
+			c.RPCEndpoint = f.values.RPCEndpoint
+		case "swap-enable":
+			c.SwapEnable = f.values.SwapEnable
+		case "network":
+			c.Network = f.values.Network
+		case "network-id":
+			c.NetworkID = f.values.NetworkID
+		case "bootnodes":
+			c.Bootnodes = splitList(f.bootnodes)
+		}
+	})
+
+	if c.DataDir == "" {
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return nil, fmt.Errorf("data dir is not set and no default is available: %w", err)
+		}
+		c.DataDir = filepath.Join(dir, "swarm-mobile")
+	}
+
+	return c, c.validate()
+}
+
+func (c *Config) loadEnv() error {
+	for key, value := range map[string]*string{
+		"DATA_DIR":        &c.DataDir,
+		"PASSWORD":        &c.Password,
+		"WELCOME_MESSAGE": &c.WelcomeMessage,
+		"NAT_ADDRESS":     &c.NATAddress,
+		"RPC_ENDPOINT":    &c.RPCEndpoint,
+		"NETWORK":         &c.Network,
+	} {
+		if v, ok := os.LookupEnv(envPrefix + key); ok {
+			*value = v
+		}
+	}
+
+	if v, ok := os.LookupEnv(envPrefix + "SWAP_ENABLE"); ok {
+		swapEnable, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("invalid %sSWAP_ENABLE: %w", envPrefix, err)
+		}
+		c.SwapEnable = swapEnable
+	}
+
+	if v, ok := os.LookupEnv(envPrefix + "NETWORK_ID"); ok {
+		networkID, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid %sNETWORK_ID: %w", envPrefix, err)
+		}
+		c.NetworkID = networkID
+	}
+	if v, ok := os.LookupEnv(envPrefix + "BOOTNODES"); ok {
+		c.Bootnodes = splitList(v)
+	}
+	return nil
+}
+
+// splitList splits a comma separated list, dropping the blank items.
+func splitList(s string) []string {
+	items := []string{}
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// SwarmNetwork returns the network the node joins.
+func (c *Config) SwarmNetwork() (node.Network, error) {
+	switch c.Network {
+	case "", node.MainnetNetworkName, node.TestnetNetworkName:
+		if c.NetworkID != 0 || len(c.Bootnodes) > 0 {
+			return node.Network{}, fmt.Errorf("network ID and bootnodes only apply to the %s network", node.CustomNetworkName)
+		}
+		if c.Network == node.TestnetNetworkName {
+			return node.Testnet, nil
+		}
+		return node.Mainnet, nil
+	case node.CustomNetworkName:
+		return node.CustomNetwork(c.NetworkID, c.Bootnodes)
+	default:
+		return node.Network{}, fmt.Errorf("unknown network %q", c.Network)
+	}
+}
+
+func (c *Config) validate() error {
+	if c.Password == "" {
+		return fmt.Errorf("password cannot be blank")
+	}
+	if c.SwapEnable && c.RPCEndpoint == "" {
+		return fmt.Errorf("rpc endpoint is required in light mode")
+	}
+	if !c.SwapEnable && c.RPCEndpoint != "" {
+		return fmt.Errorf("rpc endpoint must be empty in ultra-light mode")
+	}
+	if _, err := c.SwarmNetwork(); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package daemon
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/Solar-Punk-Ltd/swarm-mobile/internal/node"
+)
+
+// loadConfig resolves the config of the command line args, with the config file written from file if not empty.
+func loadConfig(t *testing.T, file string, args ...string) (*Config, error) {
+	t.Helper()
+	if file != "" {
+		path := filepath.Join(t.TempDir(), "config.json")
+		if err := os.WriteFile(path, []byte(file), 0600); err != nil {
+			t.Fatal(err)
+		}
+		args = append([]string{"--config", path}, args...)
+	}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	f := RegisterFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+	return f.Config()
+}
+
+func TestConfigPrecedence(t *testing.T) {
+	file := `{"dataDir": "/file", "password": "file", "welcomeMessage": "file", "natAddress": "file:1634"}`
+	t.Setenv(envPrefix+"PASSWORD", "env")
+	t.Setenv(envPrefix+"WELCOME_MESSAGE", "env")
+
+	c, err := loadConfig(t, file, "--password", "flag")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Config{
+		DataDir:        "/file",
+		Password:       "flag",
+		WelcomeMessage: "env",
+		NATAddress:     "file:1634",
+	}
+	if !reflect.DeepEqual(*c, want) {
+		t.Errorf("got %+v, want %+v", *c, want)
+	}
+}
+
+func TestConfigSwapEnable(t *testing.T) {
+	file := `{"password": "file", "swapEnable": true, "rpcEndpoint": "http://file"}`
+
+	c, err := loadConfig(t, file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !c.SwapEnable || c.RPCEndpoint != "http://file" {
+		t.Errorf("got %+v", *c)
+	}
+
+	// a flag set to its default value still overrides the other sources, a blank environment variable too
+	t.Setenv(envPrefix+"RPC_ENDPOINT", "")
+	c, err = loadConfig(t, file, "--swap-enable=false")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.SwapEnable {
+		t.Error("the swap-enable flag does not override the config file")
+	}
+
+	t.Setenv(envPrefix+"SWAP_ENABLE", "false")
+	if _, err := loadConfig(t, file, "--rpc-endpoint", "http://flag"); err == nil {
+		t.Error("expected an error for an rpc endpoint in ultra-light mode")
+	}
+
+	t.Setenv(envPrefix+"SWAP_ENABLE", "maybe")
+	if _, err := loadConfig(t, file); err == nil {
+		t.Error("expected an error for an invalid SWAP_ENABLE")
+	}
+}
+
+func TestConfigNetwork(t *testing.T) {
+	file := `{"password": "file", "network": "custom", "networkID": 5, "bootnodes": ["/dnsaddr/file.example.org"]}`
+	t.Setenv(envPrefix+"BOOTNODES", "/dnsaddr/a.example.org, /dnsaddr/b.example.org")
+
+	c, err := loadConfig(t, file, "--network-id", "7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	network, err := c.SwarmNetwork()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"/dnsaddr/a.example.org", "/dnsaddr/b.example.org"}
+	if network.Name != node.CustomNetworkName || network.NetworkID != 7 || !reflect.DeepEqual(network.Bootnodes, want) {
+		t.Errorf("got network %+v", network)
+	}
+
+	t.Setenv(envPrefix+"BOOTNODES", "")
+	c, err = loadConfig(t, `{"password": "file"}`, "--network", "testnet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if network, err := c.SwarmNetwork(); err != nil || network.Name != node.TestnetNetworkName {
+		t.Errorf("got network %+v, %v", network, err)
+	}
+}
+
+func TestConfigDefaults(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", "/config")
+	t.Setenv("HOME", "/home")
+	c, err := loadConfig(t, "", "--password", "flag")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(dir, "swarm-mobile"); c.DataDir != want {
+		t.Errorf("got data dir %q, want %q", c.DataDir, want)
+	}
+	if network, err := c.SwarmNetwork(); err != nil || network.Name != node.MainnetNetworkName {
+		t.Errorf("got network %+v, %v", network, err)
+	}
+}
+
+func TestConfigErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		args []string
+	}{
+		{"blank password", "", []string{"--data-dir", "/data"}},
+		{"light mode without rpc endpoint", "", []string{"--password", "p", "--swap-enable"}},
+		{"invalid config file", "{", []string{"--password", "p"}},
+		{"unknown network", "", []string{"--password", "p", "--network", "devnet"}},
+		{"custom network without bootnodes", "", []string{"--password", "p", "--network", "custom", "--network-id", "7"}},
+		{"custom network with the mainnet ID", "", []string{"--password", "p", "--network", "custom", "--network-id", "1", "--bootnodes", "/dnsaddr/a.example.org"}},
+		{"bootnodes of mainnet", "", []string{"--password", "p", "--bootnodes", "/dnsaddr/a.example.org"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := loadConfig(t, tt.file, tt.args...); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+
+	t.Run("invalid network ID variable", func(t *testing.T) {
+		t.Setenv(envPrefix+"NETWORK_ID", "seven")
+		if _, err := loadConfig(t, "", "--password", "p", "--network", "custom"); err == nil {
+			t.Fatal("expected an error")
+		}
+	})
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	f := RegisterFlags(fs)
+	if err := fs.Parse([]string{"--config", filepath.Join(t.TempDir(), "missing.json"), "--password", "p"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Config(); err == nil {
+		t.Fatal("expected an error for a missing config file")
+	}
+}
@@ -0,0 +1,70 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	beelog "github.com/ethersphere/bee/v2/pkg/log"
+
+	"github.com/Solar-Punk-Ltd/swarm-mobile/internal/node"
+)
+
+const statusInterval = time.Minute
+
+// Run starts a node without any GUI and blocks until SIGINT or SIGTERM is received.
+func Run(c *Config) error {
+	log.SetOutput(os.Stdout)
+	beelog.ModifyDefaults(beelog.WithSink(os.Stdout))
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	welcomeMessage := c.WelcomeMessage
+	if welcomeMessage == "" {
+		welcomeMessage = node.DefaultWelcomeMessage
+	}
+
+	network, err := c.SwarmNetwork()
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Starting Bee in headless mode, datadir: %s, network: %s (%d), swap enable: %t", c.DataDir, network.Name, network.NetworkID, c.SwapEnable)
+	n := node.New()
+	err = n.Start(&node.Options{
+		DataDir:        c.DataDir,
+		Password:       c.Password,
+		WelcomeMessage: welcomeMessage,
+		NATAddress:     c.NATAddress,
+		RPCEndpoint:    c.RPCEndpoint,
+		SwapEnable:     c.SwapEnable,
+		Network:        network,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to start node: %w", err)
+	}
+	log.Printf("Node started in %s mode, overlay address: %s", n.BeeNodeMode(), n.OverlayEthAddress())
+
+	ticker := time.NewTicker(statusInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			log.Printf("Connected with %d peers", n.ConnectedPeerCount())
+		case <-ctx.Done():
+			log.Println("Received interrupt signal, shutting down")
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), node.ShutdownTimeout)
+			defer cancel()
+			if err := n.Shutdown(shutdownCtx); err != nil {
+				return err
+			}
+			log.Println("Node stopped")
+			return nil
+		}
+	}
+}
@@ -0,0 +1,248 @@
+package history
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/storage"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+const (
+	itemPrefix = "item/"
+	refPrefix  = "ref/"
+	// ManifestMimetype is the type of the items that are the manifest of an uploaded folder.
+	ManifestMimetype = "application/swarm-manifest"
+)
+
+var ErrNotFound = errors.New("upload not found")
+
+// Order is the order of the items returned by Search.
+type Order int
+
+const (
+	ByDate Order = iota
+	ByName
+	BySize
+)
+
+// Item is an uploaded file or folder.
+type Item struct {
+	Name      string
+	Reference string
+	Size      int64
+	Timestamp time.Time
+	Mimetype  string
+	BatchID   string
+}
+
+// IsManifest reports whether the item is an uploaded folder.
+func (item Item) IsManifest() bool {
+	return item.Mimetype == ManifestMimetype
+}
+
+// Store keeps the upload history in a leveldb database, newest items first.
+type Store struct {
+	db       *leveldb.DB
+	onChange func()
+}
+
+// Open opens the store in dir, or an in-memory store if dir is empty.
+func Open(dir string) (*Store, error) {
+	var (
+		db  *leveldb.DB
+		err error
+	)
+	if dir == "" {
+		db, err = leveldb.Open(storage.NewMemStorage(), nil)
+	} else {
+		db, err = leveldb.OpenFile(dir, nil)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open upload history: %w", err)
+	}
+	return &Store{db: db}, nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// SetOnChange sets a function called after items are added or deleted.
+func (s *Store) SetOnChange(f func()) {
+	s.onChange = f
+}
+
+func (s *Store) write(batch *leveldb.Batch) error {
+	if err := s.db.Write(batch, nil); err != nil {
+		return err
+	}
+	if s.onChange != nil {
+		s.onChange()
+	}
+	return nil
+}
+
+// Add appends an item to the history. An earlier item with the same reference is replaced.
+func (s *Store) Add(item Item) error {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+
+	batch := new(leveldb.Batch)
+	oldKey, err := s.db.Get(refKey(item.Reference), nil)
+	switch {
+	case err == nil:
+		batch.Delete(oldKey)
+	case !errors.Is(err, leveldb.ErrNotFound):
+		return err
+	}
+
+	key := itemKey(item)
+	batch.Put(key, data)
+	batch.Put(refKey(item.Reference), key)
+	return s.write(batch)
+}
+
+// Delete removes the item with the given reference.
+func (s *Store) Delete(reference string) error {
+	key, err := s.db.Get(refKey(reference), nil)
+	if errors.Is(err, leveldb.ErrNotFound) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	batch := new(leveldb.Batch)
+	batch.Delete(key)
+	batch.Delete(refKey(reference))
+	return s.write(batch)
+}
+
+// List returns at most limit items starting at offset, newest first.
+func (s *Store) List(offset, limit int) ([]Item, error) {
+	return s.Search("", ByDate, offset, limit)
+}
+
+// Search returns at most limit items starting at offset whose name or reference
+// contains the query, ignoring case. Items are ordered newest first, by name or
+// largest first.
+func (s *Store) Search(query string, order Order, offset, limit int) ([]Item, error) {
+	if order == ByDate {
+		return s.search(query, offset, limit)
+	}
+
+	// the keys are ordered by date, other orders need all matching items
+	items, err := s.search(query, 0, -1)
+	if err != nil {
+		return nil, err
+	}
+	switch order {
+	case ByName:
+		sort.SliceStable(items, func(a, b int) bool {
+			return strings.ToLower(items[a].Name) < strings.ToLower(items[b].Name)
+		})
+	case BySize:
+		sort.SliceStable(items, func(a, b int) bool {
+			return items[a].Size > items[b].Size
+		})
+	}
+
+	if offset >= len(items) {
+		return []Item{}, nil
+	}
+	items = items[offset:]
+	if len(items) > limit {
+		items = items[:limit]
+	}
+	return items, nil
+}
+
+// search returns the matching items newest first, all of them if limit is negative.
+func (s *Store) search(query string, offset, limit int) ([]Item, error) {
+	query = strings.ToLower(query)
+	items := []Item{}
+	iter := s.db.NewIterator(util.BytesPrefix([]byte(itemPrefix)), nil)
+	defer iter.Release()
+
+	for ok := iter.Last(); ok && (limit < 0 || len(items) < limit); ok = iter.Prev() {
+		var item Item
+		if err := json.Unmarshal(iter.Value(), &item); err != nil {
+			return nil, err
+		}
+		if query != "" &&
+			!strings.Contains(strings.ToLower(item.Name), query) &&
+			!strings.Contains(strings.ToLower(item.Reference), query) {
+			continue
+		}
+		if offset > 0 {
+			offset--
+			continue
+		}
+		items = append(items, item)
+	}
+	return items, iter.Error()
+}
+
+// Count returns the number of items in the history.
+func (s *Store) Count() (int, error) {
+	count := 0
+	iter := s.db.NewIterator(util.BytesPrefix([]byte(refPrefix)), nil)
+	defer iter.Release()
+	for iter.Next() {
+		count++
+	}
+	return count, iter.Error()
+}
+
+// Import adds the items of a JSON array, as the upload history used to be stored in the preferences.
+func (s *Store) Import(data []byte) (int, error) {
+	items := []Item{}
+	if err := json.Unmarshal(data, &items); err != nil {
+		return 0, err
+	}
+	for n, item := range items {
+		if err := s.Add(item); err != nil {
+			return n, err
+		}
+	}
+	return len(items), nil
+}
+
+// Export returns all items as a JSON array, oldest first, in the format read by Import.
+func (s *Store) Export() ([]byte, error) {
+	items := []Item{}
+	iter := s.db.NewIterator(util.BytesPrefix([]byte(itemPrefix)), nil)
+	defer iter.Release()
+	for iter.Next() {
+		var item Item
+		if err := json.Unmarshal(iter.Value(), &item); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if err := iter.Error(); err != nil {
+		return nil, err
+	}
+	return json.Marshal(items)
+}
+
+// itemKey orders the items by their timestamp.
+func itemKey(item Item) []byte {
+	key := make([]byte, 0, len(itemPrefix)+8+len(item.Reference))
+	key = append(key, itemPrefix...)
+	key = binary.BigEndian.AppendUint64(key, uint64(item.Timestamp.UnixNano()))
+	return append(key, item.Reference...)
+}
+
+func refKey(reference string) []byte {
+	return []byte(refPrefix + reference)
+}
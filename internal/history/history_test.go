@@ -0,0 +1,205 @@
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func openTestStore(t *testing.T, dir string) *Store {
+	t.Helper()
+	s, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func names(items []Item) []string {
+	names := make([]string, len(items))
+	for n, item := range items {
+		names[n] = item.Name
+	}
+	return names
+}
+
+func checkNames(t *testing.T, items []Item, want ...string) {
+	t.Helper()
+	got := names(items)
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+var testTime = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// addTestItems adds b.txt, a.png and C.txt, in this order, one minute apart.
+func addTestItems(t *testing.T, s *Store) {
+	t.Helper()
+	items := []Item{
+		{Name: "b.txt", Reference: "bbbb", Size: 10, Timestamp: testTime},
+		{Name: "a.png", Reference: "aaaa", Size: 30, Timestamp: testTime.Add(time.Minute)},
+		{Name: "C.txt", Reference: "cccc", Size: 20, Timestamp: testTime.Add(2 * time.Minute)},
+	}
+	for _, item := range items {
+		if err := s.Add(item); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestAddReplace(t *testing.T) {
+	s := openTestStore(t, t.TempDir())
+	addTestItems(t, s)
+
+	if err := s.Add(Item{Name: "b2.txt", Reference: "bbbb", Size: 10, Timestamp: testTime.Add(3 * time.Minute)}); err != nil {
+		t.Fatal(err)
+	}
+	items, err := s.List(0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkNames(t, items, "b2.txt", "C.txt", "a.png")
+	if count, err := s.Count(); err != nil || count != 3 {
+		t.Errorf("got count %d, %v", count, err)
+	}
+}
+
+func TestDelete(t *testing.T) {
+	s := openTestStore(t, "")
+	addTestItems(t, s)
+
+	if err := s.Delete("aaaa"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Delete("aaaa"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("got error %v, want %v", err, ErrNotFound)
+	}
+	items, err := s.List(0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkNames(t, items, "C.txt", "b.txt")
+}
+
+func TestSearch(t *testing.T) {
+	s := openTestStore(t, "")
+	addTestItems(t, s)
+
+	tests := []struct {
+		name   string
+		query  string
+		order  Order
+		offset int
+		limit  int
+		want   []string
+	}{
+		{"by date", "", ByDate, 0, 10, []string{"C.txt", "a.png", "b.txt"}},
+		{"by name ignoring case", "", ByName, 0, 10, []string{"a.png", "b.txt", "C.txt"}},
+		{"by size", "", BySize, 0, 10, []string{"a.png", "C.txt", "b.txt"}},
+		{"name query", "TXT", ByDate, 0, 10, []string{"C.txt", "b.txt"}},
+		{"reference query", "aaa", ByDate, 0, 10, []string{"a.png"}},
+		{"no match", "zip", ByName, 0, 10, []string{}},
+		{"first page", "", ByDate, 0, 2, []string{"C.txt", "a.png"}},
+		{"second page", "", ByDate, 2, 2, []string{"b.txt"}},
+		{"second page by name", "", ByName, 1, 1, []string{"b.txt"}},
+		{"second page of query", "txt", BySize, 1, 2, []string{"b.txt"}},
+		{"past the end", "", BySize, 3, 2, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, err := s.Search(tt.query, tt.order, tt.offset, tt.limit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkNames(t, items, tt.want...)
+		})
+	}
+}
+
+func TestReopen(t *testing.T) {
+	dir := t.TempDir()
+	s, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addTestItems(t, s)
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	items, err := openTestStore(t, dir).List(0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkNames(t, items, "C.txt", "a.png", "b.txt")
+}
+
+// TestImport migrates the uploads as they were saved in the preferences.
+func TestImport(t *testing.T) {
+	prefs := `[
+		{"Name":"old.txt","Reference":"1111","Size":5,"Timestamp":"2024-05-01T10:00:00Z","Mimetype":"text/plain","BatchID":"ff"},
+		{"Name":"new.txt","Reference":"2222","Size":7,"Timestamp":"2024-06-01T10:00:00Z","Mimetype":"text/plain","BatchID":"ff"}
+	]`
+	s := openTestStore(t, "")
+	addTestItems(t, s)
+
+	n, err := s.Import([]byte(prefs))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Errorf("got %d imported items, want 2", n)
+	}
+	items, err := s.List(0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkNames(t, items, "C.txt", "a.png", "b.txt", "new.txt", "old.txt")
+	if items[3].Mimetype != "text/plain" || items[3].BatchID != "ff" || items[3].Size != 7 {
+		t.Errorf("got item %+v", items[3])
+	}
+
+	if _, err := s.Import([]byte("not json")); err == nil {
+		t.Error("expected an error for invalid data")
+	}
+}
+
+func TestExport(t *testing.T) {
+	s := openTestStore(t, "")
+	changes := 0
+	s.SetOnChange(func() { changes++ })
+	addTestItems(t, s)
+	if err := s.Delete("bbbb"); err != nil {
+		t.Fatal(err)
+	}
+	if changes != 4 {
+		t.Errorf("got %d changes, want 4", changes)
+	}
+
+	data, err := s.Export()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var exported []Item
+	if err := json.Unmarshal(data, &exported); err != nil {
+		t.Fatal(err)
+	}
+	checkNames(t, exported, "a.png", "C.txt")
+
+	imported := openTestStore(t, "")
+	if _, err := imported.Import(data); err != nil {
+		t.Fatal(err)
+	}
+	items, err := imported.List(0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkNames(t, items, "C.txt", "a.png")
+	if !items[0].Timestamp.Equal(testTime.Add(2 * time.Minute)) {
+		t.Errorf("got timestamp %s", items[0].Timestamp)
+	}
+}
@@ -0,0 +1,95 @@
+package node
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+
+	"github.com/ethersphere/bee/v2/pkg/bigint"
+	"github.com/ethersphere/bee/v2/pkg/jsonhttp"
+)
+
+// apiURL is where bee-lite serves the bee API. The address is fixed by bee-lite.
+const apiURL = "http://127.0.0.1:1633"
+
+// ErrNotFound is returned when the bee API does not know the requested resource.
+var ErrNotFound = errors.New("not found")
+
+// APIError is a failed request to the bee API.
+type APIError struct {
+	Code    int
+	Message string
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return http.StatusText(e.Code)
+	}
+	return e.Message
+}
+
+func (e *APIError) Is(target error) bool {
+	return target == ErrNotFound && e.Code == http.StatusNotFound
+}
+
+// request calls the bee API of the running node and decodes the JSON response into out, if it is not nil.
+func (n *beeNode) request(ctx context.Context, method, path string, out any) error {
+	resp, err := n.do(ctx, method, path)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("%s %s: %w", method, path, err)
+	}
+	return nil
+}
+
+// do calls the bee API and returns the response if its status is 2xx. The caller closes the body.
+func (n *beeNode) do(ctx context.Context, method, path string) (*http.Response, error) {
+	if _, err := n.beelite(); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, apiURL+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 == 2 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+
+	apiErr := &APIError{Code: resp.StatusCode}
+	var status jsonhttp.StatusResponse
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if json.Unmarshal(body, &status) == nil {
+		apiErr.Message = status.Message
+	}
+	return nil, apiErr
+}
+
+// Health checks that the API of the running node responds.
+func (n *beeNode) Health(ctx context.Context) error {
+	return n.request(ctx, http.MethodGet, "/health", nil)
+}
+
+// bigOrZero returns the value of a big integer of an API response, zero if it is missing.
+func bigOrZero(b *bigint.BigInt) *big.Int {
+	if b == nil || b.Int == nil {
+		return new(big.Int)
+	}
+	return b.Int
+}
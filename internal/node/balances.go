@@ -0,0 +1,107 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	chaincfg "github.com/ethersphere/bee/v2/pkg/config"
+	"github.com/ethersphere/bee/v2/pkg/util/abiutil"
+	"github.com/ethersphere/go-sw3-abi/sw3abi"
+)
+
+var (
+	// MinNativeTokenBalance is the xDAI, in wei, needed to pay the gas of the chequebook deployment in light mode.
+	MinNativeTokenBalance = big.NewInt(1e16)
+	// MinBZZBalance is the xBZZ, in PLUR, needed to buy a first small postage batch in light mode.
+	MinBZZBalance = big.NewInt(1e15)
+
+	erc20ABI = abiutil.MustParseABI(sw3abi.ERC20ABIv0_6_9)
+)
+
+// Balances are the native token and xBZZ balances of an address.
+type Balances struct {
+	// NativeToken is the xDAI balance in wei.
+	NativeToken *big.Int
+	// BZZ is the xBZZ balance in PLUR.
+	BZZ *big.Int
+}
+
+// Sufficient reports whether the balances are enough to start a node in light mode.
+func (b Balances) Sufficient() bool {
+	return b.NativeToken.Cmp(MinNativeTokenBalance) >= 0 && b.BZZ.Cmp(MinBZZBalance) >= 0
+}
+
+// QueryBalances reads the balances of address from the RPC endpoint. It does not need a running node.
+// The xBZZ token is looked up from the postage contract of the chain the endpoint is on.
+func QueryBalances(ctx context.Context, rpcEndpoint string, address common.Address) (Balances, error) {
+	eth, err := ethclient.DialContext(ctx, rpcEndpoint)
+	if err != nil {
+		return Balances{}, fmt.Errorf("rpc endpoint is invalid or not reachable: %w", err)
+	}
+	defer eth.Close()
+
+	chain, err := chainOf(ctx, eth)
+	if err != nil {
+		return Balances{}, err
+	}
+
+	native, err := eth.BalanceAt(ctx, address, nil)
+	if err != nil {
+		return Balances{}, fmt.Errorf("native token balance: %w", err)
+	}
+
+	token, err := bzzToken(ctx, eth, chain)
+	if err != nil {
+		return Balances{}, err
+	}
+
+	data, err := call(ctx, eth, token, erc20ABI.Pack, "balanceOf", address)
+	if err != nil {
+		return Balances{}, fmt.Errorf("xBZZ balance: %w", err)
+	}
+	results, err := erc20ABI.Unpack("balanceOf", data)
+	if err != nil {
+		return Balances{}, fmt.Errorf("xBZZ balance: %w", err)
+	}
+	bzz, ok := results[0].(*big.Int)
+	if !ok {
+		return Balances{}, fmt.Errorf("xBZZ balance: unexpected result %v", results[0])
+	}
+
+	return Balances{NativeToken: native, BZZ: bzz}, nil
+}
+
+// bzzToken looks up the address of the xBZZ token from the postage contract of the chain.
+func bzzToken(ctx context.Context, eth *ethclient.Client, chain chaincfg.ChainConfig) (common.Address, error) {
+	postageABI := abiutil.MustParseABI(chain.PostageStampABI)
+	data, err := call(ctx, eth, chain.PostageStampAddress, postageABI.Pack, "bzzToken")
+	if err != nil {
+		return common.Address{}, fmt.Errorf("lookup xBZZ token: %w", err)
+	}
+	return common.BytesToAddress(data), nil
+}
+
+// chainOf returns the configuration of the chain the endpoint is on.
+func chainOf(ctx context.Context, eth *ethclient.Client) (chaincfg.ChainConfig, error) {
+	chainID, err := eth.ChainID(ctx)
+	if err != nil {
+		return chaincfg.ChainConfig{}, err
+	}
+	chain, ok := chaincfg.GetByChainID(chainID.Int64())
+	if !ok {
+		return chaincfg.ChainConfig{}, fmt.Errorf("no postage contract known on chain %d", chainID.Int64())
+	}
+	return chain, nil
+}
+
+func call(ctx context.Context, eth *ethclient.Client, to common.Address, pack func(string, ...any) ([]byte, error), method string, args ...any) ([]byte, error) {
+	input, err := pack(method, args...)
+	if err != nil {
+		return nil, err
+	}
+	return eth.CallContract(ctx, ethereum.CallMsg{To: &to, Data: input}, nil)
+}
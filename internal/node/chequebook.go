@@ -0,0 +1,83 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// chequebookWithdrawGasLimit is the gas limit bee sets for chequebook withdrawals.
+const chequebookWithdrawGasLimit = 95000
+
+// ChequebookDeposit transfers amount PLUR from the node wallet to the chequebook.
+// It returns as soon as the transaction is sent.
+func (n *beeNode) ChequebookDeposit(ctx context.Context, amount *big.Int) (common.Hash, error) {
+	return n.chequebookTransaction(ctx, "/chequebook/deposit?amount="+amount.String())
+}
+
+// ChequebookWithdraw transfers amount PLUR, not covered by issued cheques, from the chequebook to the node wallet.
+// It returns as soon as the transaction is sent.
+func (n *beeNode) ChequebookWithdraw(ctx context.Context, amount *big.Int) (common.Hash, error) {
+	return n.chequebookTransaction(ctx, "/chequebook/withdraw?amount="+amount.String())
+}
+
+func (n *beeNode) chequebookTransaction(ctx context.Context, path string) (common.Hash, error) {
+	resp := struct {
+		TransactionHash common.Hash `json:"transactionHash"`
+	}{}
+	if err := n.request(ctx, http.MethodPost, path, &resp); err != nil {
+		return common.Hash{}, err
+	}
+	return resp.TransactionHash, nil
+}
+
+// WaitForReceipt waits until a transaction sent by the node is mined.
+func (n *beeNode) WaitForReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	bl, err := n.beelite()
+	if err != nil {
+		return nil, err
+	}
+	return bl.TransactionService().WaitForReceipt(ctx, txHash)
+}
+
+// EstimateChequebookFee returns the estimated fee, in wei, of a chequebook deposit or withdrawal of amount PLUR
+// sent by owner, at the gas price suggested by the RPC endpoint.
+func EstimateChequebookFee(ctx context.Context, rpcEndpoint string, owner, chequebook common.Address, amount *big.Int, deposit bool) (*big.Int, error) {
+	eth, err := ethclient.DialContext(ctx, rpcEndpoint)
+	if err != nil {
+		return nil, fmt.Errorf("rpc endpoint is invalid or not reachable: %w", err)
+	}
+	defer eth.Close()
+
+	gasLimit := uint64(chequebookWithdrawGasLimit)
+	if deposit {
+		chain, err := chainOf(ctx, eth)
+		if err != nil {
+			return nil, err
+		}
+		token, err := bzzToken(ctx, eth, chain)
+		if err != nil {
+			return nil, err
+		}
+		input, err := erc20ABI.Pack("transfer", chequebook, amount)
+		if err != nil {
+			return nil, err
+		}
+		gasLimit, err = eth.EstimateGas(ctx, ethereum.CallMsg{From: owner, To: &token, Data: input})
+		if err != nil {
+			return nil, fmt.Errorf("estimate gas: %w", err)
+		}
+	}
+
+	gasPrice, err := eth.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("gas price: %w", err)
+	}
+	return new(big.Int).Mul(gasPrice, new(big.Int).SetUint64(gasLimit)), nil
+}
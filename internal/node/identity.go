@@ -0,0 +1,192 @@
+package node
+
+import (
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	_ "embed"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	mnemonicIterations = 2048
+	mnemonicSeedLen    = 64
+	keyScryptN         = 1 << 15
+	keyScryptP         = 1
+	mnemonicWordBits   = 11
+	hardenedKeyStart   = 0x80000000
+)
+
+// ErrInvalidMnemonic is returned when a mnemonic has a word out of the wordlist or a wrong checksum.
+var ErrInvalidMnemonic = errors.New("invalid mnemonic")
+
+// bip39English is the BIP-39 English wordlist.
+//
+//go:embed bip39_english.txt
+var bip39English string
+
+// DefaultDerivationPath is the BIP-44 path of the first Ethereum account of a mnemonic.
+var DefaultDerivationPath = accounts.DefaultBaseDerivationPath.String()
+
+// KeyFromHex parses a hex encoded secp256k1 private key, with or without the 0x prefix.
+func KeyFromHex(s string) (*ecdsa.PrivateKey, error) {
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(strings.TrimSpace(s), "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+	return key, nil
+}
+
+// KeyFromKeystore decrypts a V3 keystore JSON with its password.
+func KeyFromKeystore(keyJSON []byte, password string) (*ecdsa.PrivateKey, error) {
+	key, err := keystore.DecryptKey(keyJSON, password)
+	if err != nil {
+		return nil, fmt.Errorf("invalid keystore: %w", err)
+	}
+	return key.PrivateKey, nil
+}
+
+// KeyFromMnemonic derives the private key at the BIP-32 path from the seed of a BIP-39 mnemonic.
+// The words must be in the English wordlist and match the checksum.
+func KeyFromMnemonic(mnemonic, path string) (*ecdsa.PrivateKey, error) {
+	derivationPath, err := accounts.ParseDerivationPath(path)
+	if err != nil {
+		return nil, err
+	}
+	seed, err := mnemonicSeed(mnemonic, "")
+	if err != nil {
+		return nil, err
+	}
+
+	key, chainCode := hdMaster(seed)
+	for _, index := range derivationPath {
+		key, chainCode, err = hdChild(key, chainCode, index)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return crypto.ToECDSA(common.LeftPadBytes(key.Bytes(), 32))
+}
+
+// mnemonicSeed checks the words and the checksum of a BIP-39 mnemonic and returns its seed.
+func mnemonicSeed(mnemonic, passphrase string) ([]byte, error) {
+	words := strings.Fields(strings.ToLower(norm.NFKD.String(mnemonic)))
+	switch len(words) {
+	case 12, 15, 18, 21, 24:
+	default:
+		return nil, fmt.Errorf("mnemonic must have 12, 15, 18, 21 or 24 words, got %d", len(words))
+	}
+
+	// every word is 11 bits of the entropy followed by its checksum, one bit for every 32 bits of entropy
+	bits := new(big.Int)
+	for n, word := range words {
+		index, ok := mnemonicWordIndex()[word]
+		if !ok {
+			return nil, fmt.Errorf("%w: word %d %q is not in the wordlist", ErrInvalidMnemonic, n+1, word)
+		}
+		bits.Lsh(bits, mnemonicWordBits)
+		bits.Or(bits, big.NewInt(int64(index)))
+	}
+	checksumBits := uint(len(words) * mnemonicWordBits / 33)
+	checksum := new(big.Int).And(bits, big.NewInt(1<<checksumBits-1))
+	entropy := common.LeftPadBytes(bits.Rsh(bits, checksumBits).Bytes(), int(checksumBits*4))
+	hash := sha256.Sum256(entropy)
+	if uint64(hash[0]>>(8-checksumBits)) != checksum.Uint64() {
+		return nil, fmt.Errorf("%w: wrong checksum, check the words and their order", ErrInvalidMnemonic)
+	}
+
+	salt := "mnemonic" + norm.NFKD.String(passphrase)
+	return pbkdf2.Key([]byte(strings.Join(words, " ")), []byte(salt), mnemonicIterations, mnemonicSeedLen, sha512.New), nil
+}
+
+// mnemonicWordIndex returns the position of every word of the BIP-39 English wordlist.
+var mnemonicWordIndex = sync.OnceValue(func() map[string]int {
+	index := make(map[string]int, 2048)
+	for n, word := range strings.Fields(bip39English) {
+		index[word] = n
+	}
+	return index
+})
+
+// hdMaster returns the BIP-32 master key and chain code of a seed.
+func hdMaster(seed []byte) (*big.Int, []byte) {
+	mac := hmac.New(sha512.New, []byte("Bitcoin seed"))
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+	return new(big.Int).SetBytes(sum[:32]), sum[32:]
+}
+
+// hdChild derives the BIP-32 private child key at index, hardened if index is at least 2^31.
+func hdChild(key *big.Int, chainCode []byte, index uint32) (*big.Int, []byte, error) {
+	var data []byte
+	if index >= hardenedKeyStart {
+		data = append([]byte{0}, common.LeftPadBytes(key.Bytes(), 32)...)
+	} else {
+		parent, err := crypto.ToECDSA(common.LeftPadBytes(key.Bytes(), 32))
+		if err != nil {
+			return nil, nil, err
+		}
+		data = crypto.CompressPubkey(&parent.PublicKey)
+	}
+	data = binary.BigEndian.AppendUint32(data, index)
+
+	mac := hmac.New(sha512.New, chainCode)
+	mac.Write(data)
+	sum := mac.Sum(nil)
+
+	n := crypto.S256().Params().N
+	tweak := new(big.Int).SetBytes(sum[:32])
+	child := new(big.Int).Add(tweak, key)
+	child.Mod(child, n)
+	if tweak.Cmp(n) >= 0 || child.Sign() == 0 {
+		return nil, nil, fmt.Errorf("invalid child key at index %d, use the next index", index)
+	}
+	return child, sum[32:], nil
+}
+
+// WriteSwarmKey encrypts the key with the node password and saves it as the only key of the data directory,
+// so the node starts with this identity. The other keys are created again by the node at the next start.
+// It returns ErrNodeStateExists if the data directory holds the state of another node, see ResetNodeState.
+// It returns the ethereum address of the key.
+func WriteSwarmKey(dataDir string, key *ecdsa.PrivateKey, password string) (common.Address, error) {
+	address := crypto.PubkeyToAddress(key.PublicKey)
+	if err := checkNodeState(dataDir, address); err != nil {
+		return common.Address{}, err
+	}
+	keyJSON, err := keystore.EncryptKey(&keystore.Key{Id: uuid.New(), Address: address, PrivateKey: key}, password, keyScryptN, keyScryptP)
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	if err := os.MkdirAll(dataDir, 0700); err != nil {
+		return common.Address{}, err
+	}
+	tmp, err := os.MkdirTemp(dataDir, "keys-wallet")
+	if err != nil {
+		return common.Address{}, err
+	}
+	defer os.RemoveAll(tmp)
+	if err := os.WriteFile(filepath.Join(tmp, swarmKeyFile), keyJSON, 0600); err != nil {
+		return common.Address{}, err
+	}
+	if err := replaceDir(filepath.Join(dataDir, keysDir), tmp); err != nil {
+		return common.Address{}, err
+	}
+	return address, nil
+}
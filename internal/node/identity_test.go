@@ -0,0 +1,183 @@
+package node
+
+import (
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func mustDecodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// TestHDDerivation checks the private keys of the BIP-32 test vector 1.
+func TestHDDerivation(t *testing.T) {
+	seed := "000102030405060708090a0b0c0d0e0f"
+	tests := []struct {
+		path      string
+		key       string
+		chainCode string
+	}{
+		{"m", "e8f32e723decf4051aefac8e2c93c9c5b214313817cdb01a1494b917c8436b35", "873dff81c02f525623fd1fe5167eac3a55a049de3d314bb42ee227ffed37d508"},
+		{"m/0'", "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea", "47fdacbd0f1097043b78c63c20c34ef4ed9a111d980047ad16282c7ae6236141"},
+		{"m/0'/1", "3c6cb8d0f6a264c91ea8b5030fadaa8e538b020f0a387421a12de9319dc93368", "2a7857631386ba23dacac34180dd1983734e444fdbf774041578e9b6adb37c19"},
+		{"m/0'/1/2'", "cbce0d719ecf7431d88e6a89fa1483e02e35092af60c042b1df2ff59fa424dca", "04466b9cc8e161e966409ca52986c584f07e9dc81f735db683c3ff6ec7b1503f"},
+		{"m/0'/1/2'/2", "0f479245fb19a38a1954c5c7c0ebab2f9bdfd96a17563ef28a6a4b1a2a764ef4", "cfb71883f01676f587d023cc53a35bc7f88f724b1f8c2892ac1275ac822a3edd"},
+		{"m/0'/1/2'/2/1000000000", "471b76e389e528d6de6d816857e012c5455051cad6660850e58372a6c3e6e7c8", "c783e67b921d2beb8f6b389cc646d7263b4145701dadd2161548a8b078e65e9e"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			key, chainCode := hdMaster(mustDecodeHex(t, seed))
+			if tt.path != "m" {
+				path, err := accounts.ParseDerivationPath(tt.path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				for _, index := range path {
+					key, chainCode, err = hdChild(key, chainCode, index)
+					if err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+			if got := hex.EncodeToString(common.LeftPadBytes(key.Bytes(), 32)); got != tt.key {
+				t.Errorf("got key %s, want %s", got, tt.key)
+			}
+			if got := hex.EncodeToString(chainCode); got != tt.chainCode {
+				t.Errorf("got chain code %s, want %s", got, tt.chainCode)
+			}
+		})
+	}
+}
+
+// TestMnemonicSeed checks the seeds of the BIP-39 reference vectors, with the passphrase TREZOR.
+func TestMnemonicSeed(t *testing.T) {
+	tests := []struct {
+		mnemonic string
+		seed     string
+	}{
+		{
+			"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+			"c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04",
+		},
+		{
+			"legal winner thank year wave sausage worth useful legal winner thank yellow",
+			"2e8905819b8723fe2c1d161860e5ee1830318dbf49a83bd451cfb8440c28bd6fa457fe1296106559a3c80937a1c1069be3a3a5bd381ee6260e8d9739fce1f607",
+		},
+		{
+			"letter advice cage absurd amount doctor acoustic avoid letter advice cage above",
+			"d71de856f81a8acc65e6fc851a38d4d7ec216fd0796d0a6827a3ad6ed5511a30fa280f12eb2e47ed2ac03b5c462a0358d18d69fe4f985ec81778c1b370b652a8",
+		},
+		{
+			"zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo wrong",
+			"ac27495480225222079d7be181583751e86f571027b0497b5b5d11218e0a8a13332572917f0f8e5a589620c6f15b11c61dee327651a14c34e18231052e48c069",
+		},
+		{
+			strings.Repeat("abandon ", 23) + "art",
+			"bda85446c68413707090a52022edd26a1c9462295029f2e60cd7c4f2bbd3097170af7a4d73245cafa9c3cca8d561a7c3de6f5d4a10be8ed2a5e608d68f92fcc8",
+		},
+		{
+			strings.Repeat("zoo ", 23) + "vote",
+			"dd48c104698c30cfe2b6142103248622fb7bb0ff692eebb00089b32d22484e1613912f0a5b694407be899ffd31ed3992c456cdf60f5d4564b8ba3f05a69890ad",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mnemonic, func(t *testing.T) {
+			seed, err := mnemonicSeed(tt.mnemonic, "TREZOR")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := hex.EncodeToString(seed); got != tt.seed {
+				t.Errorf("got seed %s, want %s", got, tt.seed)
+			}
+		})
+	}
+}
+
+func TestMnemonicSeedInvalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		mnemonic string
+	}{
+		{"wrong checksum", strings.Repeat("abandon ", 12)},
+		{"swapped words", "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about abandon"},
+		{"typo", "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abuot"},
+		{"unknown word", "legal winner thank year wave sausage worth useful legal winner thank yelow"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := mnemonicSeed(tt.mnemonic, ""); !errors.Is(err, ErrInvalidMnemonic) {
+				t.Fatalf("got error %v, want %v", err, ErrInvalidMnemonic)
+			}
+		})
+	}
+	if _, err := mnemonicSeed("abandon about", ""); err == nil {
+		t.Fatal("expected an error for a mnemonic of 2 words")
+	}
+}
+
+func TestKeyFromMnemonic(t *testing.T) {
+	mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	tests := []struct {
+		path    string
+		address string
+	}{
+		{DefaultDerivationPath, "0x9858EfFD232B4033E47d90003D41EC34EcaEda94"},
+		{"m/44'/60'/0'/0/1", "0x6Fac4D18c912343BF86fa7049364Dd4E424Ab9C0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			key, err := KeyFromMnemonic(mnemonic, tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := crypto.PubkeyToAddress(key.PublicKey).Hex(); got != tt.address {
+				t.Errorf("got address %s, want %s", got, tt.address)
+			}
+		})
+	}
+}
+
+func TestWriteSwarmKey(t *testing.T) {
+	dataDir := newTestDataDir(t)
+	key, err := KeyFromHex("0x4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.MkdirAll(filepath.Join(dataDir, "localstore"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := WriteSwarmKey(dataDir, key, testPassword); !errors.Is(err, ErrNodeStateExists) {
+		t.Fatalf("got error %v, want %v", err, ErrNodeStateExists)
+	}
+	if err := ResetNodeState(dataDir); err != nil {
+		t.Fatal(err)
+	}
+
+	address, err := WriteSwarmKey(dataDir, key, testPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if address != crypto.PubkeyToAddress(key.PublicKey) {
+		t.Errorf("got address %s, want the address of the key", address)
+	}
+	if err := VerifyPassword(dataDir, testPassword); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dataDir, keysDir, "libp2p_v2.key")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("the libp2p key of the previous node is kept: %v", err)
+	}
+}
@@ -0,0 +1,350 @@
+package node
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	ethkeystore "github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethersphere/bee/v2/pkg/crypto"
+	"github.com/ethersphere/bee/v2/pkg/keystore"
+	filekeystore "github.com/ethersphere/bee/v2/pkg/keystore/file"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	keysDir        = "keys"
+	swarmKeyName   = "swarm"
+	swarmKeyFile   = swarmKeyName + ".key"
+	archiveMagic   = "SWMKEYS1"
+	archiveSaltLen = 16
+	archiveKeyLen  = 32
+	archiveScryptN = 1 << 15
+	archiveScryptR = 8
+	archiveScryptP = 1
+)
+
+var (
+	// ErrInvalidArchive is returned when the data is not a key archive or the archive password is wrong.
+	ErrInvalidArchive = errors.New("invalid key archive or wrong archive password")
+	// ErrInvalidPassword is returned when the keys cannot be decrypted with the node password.
+	ErrInvalidPassword = keystore.ErrInvalidPassword
+	// ErrNodeStateExists is returned when the data directory holds the state of a node with another identity.
+	// Bee refuses to start with a swarm key that does not match the overlay saved in the statestore.
+	ErrNodeStateExists = errors.New("the data directory holds the state of another node")
+
+	// keyFiles are the keystore files of a node, they are encrypted with the node password.
+	keyFiles = []string{swarmKeyFile, "libp2p_v2.key", "pss.key"}
+	// stateDirs are the stores bee keeps in the data directory, bound to the overlay of the swarm key.
+	stateDirs = []string{"statestore", "stamperstore", "localstore", "kademlia-metrics"}
+)
+
+// KeysExist reports whether the data directory already holds the swarm key of a node.
+func KeysExist(dataDir string) (bool, error) {
+	return filekeystore.New(filepath.Join(dataDir, keysDir)).Exists(swarmKeyName)
+}
+
+// ExportKeys packs the keystore files of the data directory into an archive encrypted with password.
+// The key files stay encrypted with the node password inside the archive.
+func ExportKeys(dataDir, password string) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, name := range keyFiles {
+		data, err := os.ReadFile(filepath.Join(dataDir, keysDir, name))
+		if errors.Is(err, os.ErrNotExist) && name != swarmKeyFile {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", name, err)
+		}
+		w, err := zw.Create(name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(data); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	salt := make([]byte, archiveSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	aead, err := archiveCipher(password, salt)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	archive := append([]byte(archiveMagic), salt...)
+	archive = append(archive, nonce...)
+	return aead.Seal(archive, nonce, buf.Bytes(), []byte(archiveMagic)), nil
+}
+
+// ImportKeys decrypts the archive with password and replaces the keystore of the data directory
+// with its keys, once the swarm key is verified to decrypt with the node password.
+// It returns ErrNodeStateExists if the data directory holds the state of another node, see ResetNodeState.
+// It returns the ethereum address of the imported node.
+func ImportKeys(dataDir string, archive []byte, password, nodePassword string) (common.Address, error) {
+	files, err := openArchive(archive, password)
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	if err := os.MkdirAll(dataDir, 0700); err != nil {
+		return common.Address{}, err
+	}
+	tmp, err := os.MkdirTemp(dataDir, "keys-import")
+	if err != nil {
+		return common.Address{}, err
+	}
+	defer os.RemoveAll(tmp)
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(tmp, name), data, 0600); err != nil {
+			return common.Address{}, err
+		}
+	}
+
+	address, err := verifyKeys(tmp, nodePassword)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if err := checkNodeState(dataDir, address); err != nil {
+		return common.Address{}, err
+	}
+	if err := replaceDir(filepath.Join(dataDir, keysDir), tmp); err != nil {
+		return common.Address{}, err
+	}
+	return address, nil
+}
+
+// ResetNodeState deletes the stores of the node from the data directory: the statestore with the overlay,
+// the stamp issuers, the cached chunks and the peer metrics. The keys and the upload history are kept.
+func ResetNodeState(dataDir string) error {
+	for _, name := range stateDirs {
+		if err := os.RemoveAll(filepath.Join(dataDir, name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkNodeState returns ErrNodeStateExists if the data directory holds the state of a node
+// whose swarm key is not the key of address.
+func checkNodeState(dataDir string, address common.Address) error {
+	for _, name := range stateDirs {
+		_, err := os.Stat(filepath.Join(dataDir, name))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if current, err := swarmKeyAddress(dataDir); err == nil && current == address {
+			return nil
+		}
+		return ErrNodeStateExists
+	}
+	return nil
+}
+
+// swarmKeyAddress returns the ethereum address saved in clear in the swarm key of the data directory.
+func swarmKeyAddress(dataDir string) (common.Address, error) {
+	data, err := os.ReadFile(filepath.Join(dataDir, keysDir, swarmKeyFile))
+	if err != nil {
+		return common.Address{}, err
+	}
+	var key struct {
+		Address string `json:"address"`
+	}
+	if err := json.Unmarshal(data, &key); err != nil {
+		return common.Address{}, err
+	}
+	if !common.IsHexAddress(key.Address) {
+		return common.Address{}, fmt.Errorf("swarm key has no address")
+	}
+	return common.HexToAddress(key.Address), nil
+}
+
+// replaceDir moves src to dst. The previous dst is kept until the move succeeds, and restored if it fails.
+func replaceDir(dst, src string) error {
+	old := dst + ".old"
+	if err := os.RemoveAll(old); err != nil {
+		return err
+	}
+	if err := os.Rename(dst, old); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if err := os.Rename(src, dst); err != nil {
+		if rerr := os.Rename(old, dst); rerr != nil && !errors.Is(rerr, os.ErrNotExist) {
+			return errors.Join(err, rerr)
+		}
+		return err
+	}
+	return os.RemoveAll(old)
+}
+
+// openArchive decrypts the archive and returns the key files in it by name.
+func openArchive(archive []byte, password string) (map[string][]byte, error) {
+	if len(archive) < len(archiveMagic)+archiveSaltLen || string(archive[:len(archiveMagic)]) != archiveMagic {
+		return nil, ErrInvalidArchive
+	}
+	salt := archive[len(archiveMagic) : len(archiveMagic)+archiveSaltLen]
+	aead, err := archiveCipher(password, salt)
+	if err != nil {
+		return nil, err
+	}
+	rest := archive[len(archiveMagic)+archiveSaltLen:]
+	if len(rest) < aead.NonceSize() {
+		return nil, ErrInvalidArchive
+	}
+	data, err := aead.Open(nil, rest[:aead.NonceSize()], rest[aead.NonceSize():], []byte(archiveMagic))
+	if err != nil {
+		return nil, ErrInvalidArchive
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, ErrInvalidArchive
+	}
+	files := map[string][]byte{}
+	for _, f := range zr.File {
+		if !isKeyFile(f.Name) {
+			return nil, fmt.Errorf("unexpected file %q in key archive", f.Name)
+		}
+		r, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		content, err := io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			return nil, err
+		}
+		files[f.Name] = content
+	}
+	if _, ok := files[swarmKeyFile]; !ok {
+		return nil, fmt.Errorf("key archive has no swarm key")
+	}
+	return files, nil
+}
+
+// verifyKeys decrypts the swarm key in dir with the node password and returns its ethereum address.
+func verifyKeys(dir, nodePassword string) (common.Address, error) {
+	ks := filekeystore.New(dir)
+	exists, err := ks.Exists(swarmKeyName)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if !exists {
+		return common.Address{}, fmt.Errorf("swarm key not found")
+	}
+	pk, _, err := ks.Key(swarmKeyName, nodePassword, crypto.EDGSecp256_K1)
+	if err != nil {
+		return common.Address{}, err
+	}
+	address, err := crypto.NewEthereumAddress(pk.PublicKey)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return common.BytesToAddress(address), nil
+}
+
+func isKeyFile(name string) bool {
+	for _, k := range keyFiles {
+		if name == k {
+			return true
+		}
+	}
+	return false
+}
+
+func archiveCipher(password string, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(password), salt, archiveScryptN, archiveScryptR, archiveScryptP, archiveKeyLen)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// VerifyPassword checks that the swarm key of the data directory decrypts with the node password.
+func VerifyPassword(dataDir, password string) error {
+	_, err := verifyKeys(filepath.Join(dataDir, keysDir), password)
+	return err
+}
+
+// ChangePassword re-encrypts every key file of the data directory with the new node password.
+// The keystore is replaced only once all keys are decrypted with the old password.
+func ChangePassword(dataDir, oldPassword, newPassword string) error {
+	dir := filepath.Join(dataDir, keysDir)
+	tmp, err := os.MkdirTemp(dataDir, "keys-password")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	for _, name := range keyFiles {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if errors.Is(err, os.ErrNotExist) && name != swarmKeyFile {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("read %s: %w", name, err)
+		}
+		data, err = reencryptKey(data, oldPassword, newPassword)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		if err := os.WriteFile(filepath.Join(tmp, name), data, 0600); err != nil {
+			return err
+		}
+	}
+	return replaceDir(dir, tmp)
+}
+
+// reencryptKey decrypts the payload of a V3 key file and encrypts it again with the new password,
+// keeping the other fields. The payload is not decoded, so it works for the keys of every curve.
+func reencryptKey(data []byte, oldPassword, newPassword string) ([]byte, error) {
+	var key map[string]json.RawMessage
+	if err := json.Unmarshal(data, &key); err != nil {
+		return nil, err
+	}
+	var cryptoJSON ethkeystore.CryptoJSON
+	if err := json.Unmarshal(key["crypto"], &cryptoJSON); err != nil {
+		return nil, err
+	}
+	payload, err := ethkeystore.DecryptDataV3(cryptoJSON, oldPassword)
+	if errors.Is(err, ethkeystore.ErrDecrypt) {
+		return nil, ErrInvalidPassword
+	}
+	if err != nil {
+		return nil, err
+	}
+	cryptoJSON, err = ethkeystore.EncryptDataV3(payload, []byte(newPassword), keyScryptN, keyScryptP)
+	if err != nil {
+		return nil, err
+	}
+	if key["crypto"], err = json.Marshal(cryptoJSON); err != nil {
+		return nil, err
+	}
+	return json.Marshal(key)
+}
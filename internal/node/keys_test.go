@@ -0,0 +1,221 @@
+package node
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethersphere/bee/v2/pkg/crypto"
+	filekeystore "github.com/ethersphere/bee/v2/pkg/keystore/file"
+)
+
+const testPassword = "node password"
+
+// newTestDataDir returns a data directory with the keys of a new node encrypted with testPassword.
+func newTestDataDir(t *testing.T) string {
+	t.Helper()
+	dataDir := t.TempDir()
+	ks := filekeystore.New(filepath.Join(dataDir, keysDir))
+	if _, _, err := ks.Key(swarmKeyName, testPassword, crypto.EDGSecp256_K1); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := ks.Key("libp2p_v2", testPassword, crypto.EDGSecp256_R1); err != nil {
+		t.Fatal(err)
+	}
+	return dataDir
+}
+
+func readKeyFile(t *testing.T, dataDir, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dataDir, keysDir, name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestExportImportKeys(t *testing.T) {
+	src := newTestDataDir(t)
+	archive, err := ExportKeys(src, "archive password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := swarmKeyAddress(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dst := t.TempDir()
+	address, err := ImportKeys(dst, archive, "archive password", testPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if address != want {
+		t.Errorf("got address %s, want %s", address, want)
+	}
+	for _, name := range []string{swarmKeyFile, "libp2p_v2.key"} {
+		if !bytes.Equal(readKeyFile(t, src, name), readKeyFile(t, dst, name)) {
+			t.Errorf("%s differs after the import", name)
+		}
+	}
+	if err := VerifyPassword(dst, testPassword); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestImportKeysErrors(t *testing.T) {
+	archive, err := ExportKeys(newTestDataDir(t), "archive password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	badMagic := bytes.Clone(archive)
+	badMagic[0] ^= 0xff
+
+	tests := []struct {
+		name         string
+		archive      []byte
+		password     string
+		nodePassword string
+		wantErr      error
+	}{
+		{"wrong archive password", archive, "wrong", testPassword, ErrInvalidArchive},
+		{"wrong node password", archive, "archive password", "wrong", ErrInvalidPassword},
+		{"bad magic", badMagic, "archive password", testPassword, ErrInvalidArchive},
+		{"truncated archive", archive[:len(archive)-10], "archive password", testPassword, ErrInvalidArchive},
+		{"truncated header", archive[:len(archiveMagic)+4], "archive password", testPassword, ErrInvalidArchive},
+		{"empty", nil, "archive password", testPassword, ErrInvalidArchive},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dataDir := newTestDataDir(t)
+			before := readKeyFile(t, dataDir, swarmKeyFile)
+			_, err := ImportKeys(dataDir, tt.archive, tt.password, tt.nodePassword)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+			if !bytes.Equal(before, readKeyFile(t, dataDir, swarmKeyFile)) {
+				t.Error("the keys were replaced by a failed import")
+			}
+		})
+	}
+}
+
+func TestImportKeysNodeState(t *testing.T) {
+	src := newTestDataDir(t)
+	archive, err := ExportKeys(src, "archive password")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dataDir := newTestDataDir(t)
+	if err := os.MkdirAll(filepath.Join(dataDir, "statestore"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ImportKeys(dataDir, archive, "archive password", testPassword); !errors.Is(err, ErrNodeStateExists) {
+		t.Fatalf("got error %v, want %v", err, ErrNodeStateExists)
+	}
+	if err := ResetNodeState(dataDir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dataDir, "statestore")); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("statestore not deleted: %v", err)
+	}
+	if _, err := ImportKeys(dataDir, archive, "archive password", testPassword); err != nil {
+		t.Fatal(err)
+	}
+
+	// the state belongs to the imported node now, importing its keys again keeps it
+	if err := os.MkdirAll(filepath.Join(dataDir, "statestore"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ImportKeys(dataDir, archive, "archive password", testPassword); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestChangePassword(t *testing.T) {
+	dataDir := newTestDataDir(t)
+	ks := filekeystore.New(filepath.Join(dataDir, keysDir))
+	swarmKey, _, err := ks.Key(swarmKeyName, testPassword, crypto.EDGSecp256_K1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	libp2pKey, _, err := ks.Key("libp2p_v2", testPassword, crypto.EDGSecp256_R1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ChangePassword(dataDir, testPassword, "new password"); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyPassword(dataDir, testPassword); !errors.Is(err, ErrInvalidPassword) {
+		t.Fatalf("the old password still works: %v", err)
+	}
+	if err := VerifyPassword(dataDir, "new password"); err != nil {
+		t.Fatal(err)
+	}
+
+	// the keys of both curves are the same, encrypted with the new password
+	gotSwarm, _, err := ks.Key(swarmKeyName, "new password", crypto.EDGSecp256_K1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !gotSwarm.Equal(swarmKey) {
+		t.Error("the swarm key changed")
+	}
+	gotLibp2p, _, err := ks.Key("libp2p_v2", "new password", crypto.EDGSecp256_R1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !gotLibp2p.Equal(libp2pKey) {
+		t.Error("the libp2p key changed")
+	}
+}
+
+func TestChangePasswordWrongPassword(t *testing.T) {
+	dataDir := newTestDataDir(t)
+	before := readKeyFile(t, dataDir, swarmKeyFile)
+	if err := ChangePassword(dataDir, "wrong", "new password"); !errors.Is(err, ErrInvalidPassword) {
+		t.Fatalf("got error %v, want %v", err, ErrInvalidPassword)
+	}
+	if !bytes.Equal(before, readKeyFile(t, dataDir, swarmKeyFile)) {
+		t.Error("the keys were changed with a wrong password")
+	}
+	if err := VerifyPassword(dataDir, testPassword); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReencryptKey(t *testing.T) {
+	data := readKeyFile(t, newTestDataDir(t), swarmKeyFile)
+	reencrypted, err := reencryptKey(data, testPassword, "new password")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var before, after map[string]json.RawMessage
+	if err := json.Unmarshal(data, &before); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(reencrypted, &after); err != nil {
+		t.Fatal(err)
+	}
+	for field, value := range before {
+		if field != "crypto" && !bytes.Equal(value, after[field]) {
+			t.Errorf("field %s changed from %s to %s", field, value, after[field])
+		}
+	}
+	if bytes.Equal(before["crypto"], after["crypto"]) {
+		t.Error("the payload is not encrypted again")
+	}
+
+	if _, err := reencryptKey(reencrypted, testPassword, "other"); !errors.Is(err, ErrInvalidPassword) {
+		t.Fatalf("got error %v, want %v", err, ErrInvalidPassword)
+	}
+	if _, err := reencryptKey([]byte("{"), testPassword, "other"); err == nil {
+		t.Fatal("expected an error for an invalid key file")
+	}
+}
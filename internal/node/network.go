@@ -0,0 +1,98 @@
+package node
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	chaincfg "github.com/ethersphere/bee/v2/pkg/config"
+	"github.com/multiformats/go-multiaddr"
+)
+
+const (
+	MainnetNetworkName = "mainnet"
+	TestnetNetworkName = "testnet"
+	CustomNetworkName  = "custom"
+)
+
+// Network describes a swarm network the node can join.
+type Network struct {
+	Name      string
+	NetworkID uint64
+	// ChainID is the chain the network's contracts live on, 0 if unknown.
+	ChainID   int64
+	Mainnet   bool
+	Bootnodes []string
+	// BlockTime is the average time between blocks of the chain.
+	BlockTime time.Duration
+	// Gateway is the URL of a public gateway serving the content of the network, empty if there is none.
+	Gateway string
+}
+
+var (
+	MainnetBootnodes = []string{
+		"/dnsaddr/mainnet.ethswarm.org",
+	}
+
+	TestnetBootnodes = []string{
+		"/dnsaddr/testnet.ethswarm.org",
+	}
+
+	Mainnet = Network{
+		Name:      MainnetNetworkName,
+		NetworkID: chaincfg.Mainnet.NetworkID,
+		ChainID:   chaincfg.Mainnet.ChainID,
+		Mainnet:   true,
+		Bootnodes: MainnetBootnodes,
+		BlockTime: 5 * time.Second,
+		Gateway:   "https://api.gateway.ethswarm.org",
+	}
+
+	Testnet = Network{
+		Name:      TestnetNetworkName,
+		NetworkID: chaincfg.Testnet.NetworkID,
+		ChainID:   chaincfg.Testnet.ChainID,
+		Mainnet:   false,
+		Bootnodes: TestnetBootnodes,
+		BlockTime: 15 * time.Second,
+	}
+)
+
+// CustomNetwork returns a network with the given ID and bootnodes. The bootnodes must be valid multiaddrs.
+func CustomNetwork(networkID uint64, bootnodes []string) (Network, error) {
+	if networkID == Mainnet.NetworkID {
+		return Network{}, fmt.Errorf("network ID %d is reserved for mainnet", networkID)
+	}
+
+	nodes := []string{}
+	for _, b := range bootnodes {
+		b = strings.TrimSpace(b)
+		if b == "" {
+			continue
+		}
+		if _, err := multiaddr.NewMultiaddr(b); err != nil {
+			return Network{}, fmt.Errorf("invalid bootnode %q: %w", b, err)
+		}
+		nodes = append(nodes, b)
+	}
+	if len(nodes) == 0 {
+		return Network{}, fmt.Errorf("at least one bootnode is required for a custom network")
+	}
+
+	// bee-lite uses the block time of mainnet for unknown networks
+	chainID := int64(0)
+	blockTime := Mainnet.BlockTime
+	if networkID == Testnet.NetworkID {
+		chainID = Testnet.ChainID
+		blockTime = Testnet.BlockTime
+	}
+
+	return Network{
+		Name:      CustomNetworkName,
+		NetworkID: networkID,
+		ChainID:   chainID,
+		Mainnet:   false,
+		Bootnodes: nodes,
+		BlockTime: blockTime,
+	}, nil
+}
@@ -0,0 +1,233 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"sync"
+	"time"
+
+	beelite "github.com/Solar-Punk-Ltd/bee-lite"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethersphere/bee/v2/pkg/api"
+	"github.com/ethersphere/bee/v2/pkg/file/redundancy"
+	"github.com/ethersphere/bee/v2/pkg/postage"
+	"github.com/ethersphere/bee/v2/pkg/swarm"
+)
+
+const (
+	DefaultRPCEndpoint    = "https://gnosis.publicnode.com"
+	DefaultWelcomeMessage = "Welcome from Swarm Mobile by Solar Punk"
+	ShutdownTimeout       = 30 * time.Second
+	ContentTypeTar        = "application/x-tar"
+	logLevel              = "3"
+	paymentThreshold      = "100000000"
+	cacheCapacity         = 32 * 1024 * 1024
+)
+
+var (
+	ErrNotStarted     = errors.New("node is not started")
+	ErrAlreadyStarted = errors.New("node is already started")
+)
+
+// Options holds the settings a node is started with.
+type Options struct {
+	DataDir        string
+	Password       string
+	WelcomeMessage string
+	NATAddress     string
+	RPCEndpoint    string
+	SwapEnable     bool
+	Network        Network
+}
+
+// Node is the set of operations the front ends need from a swarm node.
+type Node interface {
+	Start(o *Options) error
+	Shutdown(ctx context.Context) error
+	OverlayEthAddress() common.Address
+	BeeNodeMode() api.BeeNodeMode
+	ConnectedPeerCount() int
+	Health(ctx context.Context) error
+	AddFileBzz(ctx context.Context, batchID, filename, mimetype string, encrypt bool, reader io.Reader) (swarm.Address, error)
+	AddDirBzz(ctx context.Context, batchID, name, indexDocument, errorDocument string, encrypt bool, tarReader io.Reader) (swarm.Address, error)
+	GetBzz(ctx context.Context, address swarm.Address) (io.Reader, string, error)
+	GetUsableBatches() []*postage.StampIssuer
+	BuyStamp(amount *big.Int, depth uint64, label string, immutable bool) (common.Hash, []byte, error)
+	ChequebookBalance() (*big.Int, error)
+	ChequebookDeposit(ctx context.Context, amount *big.Int) (common.Hash, error)
+	ChequebookWithdraw(ctx context.Context, amount *big.Int) (common.Hash, error)
+	WaitForReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+	Settlements(ctx context.Context) (Settlements, error)
+	CashOut(ctx context.Context, peer string) (common.Hash, error)
+	Topology(ctx context.Context) (Topology, error)
+	Batches(ctx context.Context) ([]Batch, error)
+	ChainState(ctx context.Context) (ChainState, error)
+	Wallet(ctx context.Context) (Wallet, error)
+	TopUpBatch(ctx context.Context, batchID string, amount *big.Int) (common.Hash, error)
+	DiluteBatch(ctx context.Context, batchID string, depth uint8) (common.Hash, error)
+	Pin(ctx context.Context, address swarm.Address) error
+	Unpin(ctx context.Context, address swarm.Address) error
+	IsPinned(ctx context.Context, address swarm.Address) (bool, error)
+	Pins(ctx context.Context) ([]swarm.Address, error)
+	CheckPins(ctx context.Context, address swarm.Address, fn func(PinStat)) error
+}
+
+type beeNode struct {
+	mu sync.RWMutex
+	bl *beelite.Beelite
+}
+
+// New returns a Node backed by bee-lite. The node is not running until Start is called.
+func New() Node {
+	return &beeNode{}
+}
+
+func (n *beeNode) Start(o *Options) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.bl != nil {
+		return ErrAlreadyStarted
+	}
+
+	lo := &beelite.LiteOptions{
+		FullNodeMode:             false,
+		BootnodeMode:             false,
+		Bootnodes:                o.Network.Bootnodes,
+		DataDir:                  o.DataDir,
+		WelcomeMessage:           o.WelcomeMessage,
+		BlockchainRpcEndpoint:    o.RPCEndpoint,
+		SwapInitialDeposit:       "0",
+		PaymentThreshold:         paymentThreshold,
+		SwapEnable:               o.SwapEnable,
+		ChequebookEnable:         true,
+		UsePostageSnapshot:       false,
+		Mainnet:                  o.Network.Mainnet,
+		NetworkID:                o.Network.NetworkID,
+		NATAddr:                  o.NATAddress,
+		CacheCapacity:            cacheCapacity,
+		DBOpenFilesLimit:         50,
+		DBWriteBufferSize:        cacheCapacity,
+		DBBlockCacheCapacity:     cacheCapacity,
+		DBDisableSeeksCompaction: false,
+		RetrievalCaching:         true,
+	}
+
+	bl, err := beelite.Start(lo, o.Password, logLevel)
+	if err != nil {
+		return err
+	}
+	n.bl = bl
+	return nil
+}
+
+// Shutdown stops the node, closing the localstore and the libp2p host.
+// It returns when the node has stopped or the context is done, whichever happens first.
+func (n *beeNode) Shutdown(ctx context.Context) error {
+	n.mu.Lock()
+	bl := n.bl
+	n.bl = nil
+	n.mu.Unlock()
+	if bl == nil {
+		return ErrNotStarted
+	}
+
+	errC := make(chan error, 1)
+	go func() {
+		errC <- bl.Shutdown()
+	}()
+
+	select {
+	case err := <-errC:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("node shutdown: %w", ctx.Err())
+	}
+}
+
+func (n *beeNode) beelite() (*beelite.Beelite, error) {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	if n.bl == nil {
+		return nil, ErrNotStarted
+	}
+	return n.bl, nil
+}
+
+func (n *beeNode) OverlayEthAddress() common.Address {
+	bl, err := n.beelite()
+	if err != nil {
+		return common.Address{}
+	}
+	return bl.OverlayEthAddress()
+}
+
+func (n *beeNode) BeeNodeMode() api.BeeNodeMode {
+	bl, err := n.beelite()
+	if err != nil {
+		return api.UnknownMode
+	}
+	return bl.BeeNodeMode()
+}
+
+func (n *beeNode) ConnectedPeerCount() int {
+	bl, err := n.beelite()
+	if err != nil {
+		return 0
+	}
+	return bl.ConnectedPeerCount()
+}
+
+func (n *beeNode) AddFileBzz(ctx context.Context, batchID, filename, mimetype string, encrypt bool, reader io.Reader) (swarm.Address, error) {
+	bl, err := n.beelite()
+	if err != nil {
+		return swarm.ZeroAddress, err
+	}
+	ref, _, err := bl.AddFileBzz(ctx, batchID, filename, mimetype, false, swarm.ZeroAddress, encrypt, redundancy.NONE, reader)
+	return ref, err
+}
+
+// AddDirBzz uploads the files of a tar stream as a manifest. The index and error documents are optional.
+func (n *beeNode) AddDirBzz(ctx context.Context, batchID, name, indexDocument, errorDocument string, encrypt bool, tarReader io.Reader) (swarm.Address, error) {
+	bl, err := n.beelite()
+	if err != nil {
+		return swarm.ZeroAddress, err
+	}
+	ref, _, err := bl.AddDirBzz(ctx, batchID, name, ContentTypeTar, indexDocument, errorDocument, false, swarm.ZeroAddress, encrypt, redundancy.NONE, tarReader)
+	return ref, err
+}
+
+func (n *beeNode) GetBzz(ctx context.Context, address swarm.Address) (io.Reader, string, error) {
+	bl, err := n.beelite()
+	if err != nil {
+		return nil, "", err
+	}
+	return bl.GetBzz(ctx, address, nil, nil, nil)
+}
+
+func (n *beeNode) GetUsableBatches() []*postage.StampIssuer {
+	bl, err := n.beelite()
+	if err != nil {
+		return nil
+	}
+	return bl.GetUsableBatches()
+}
+
+func (n *beeNode) BuyStamp(amount *big.Int, depth uint64, label string, immutable bool) (common.Hash, []byte, error) {
+	bl, err := n.beelite()
+	if err != nil {
+		return common.Hash{}, nil, err
+	}
+	return bl.BuyStamp(amount, depth, label, immutable)
+}
+
+func (n *beeNode) ChequebookBalance() (*big.Int, error) {
+	bl, err := n.beelite()
+	if err != nil {
+		return nil, err
+	}
+	return bl.ChequebookBalance()
+}
@@ -0,0 +1,423 @@
+// Package nodetest provides an in-memory node.Node for the tests of the front ends.
+package nodetest
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethersphere/bee/v2/pkg/api"
+	"github.com/ethersphere/bee/v2/pkg/postage"
+	"github.com/ethersphere/bee/v2/pkg/swarm"
+
+	"github.com/Solar-Punk-Ltd/swarm-mobile/internal/node"
+)
+
+var _ node.Node = (*Node)(nil)
+
+// File is an upload kept by the fake node.
+type File struct {
+	Name     string
+	Mimetype string
+	BatchID  string
+	Encrypt  bool
+	Data     []byte
+	// IndexDocument and ErrorDocument are set for the uploads of a directory.
+	IndexDocument string
+	ErrorDocument string
+}
+
+// Node is a fake node.Node. The exported fields are the state the methods report, set them before use
+// or read them to check the calls. Uploads are kept in memory and can be downloaded by their reference.
+// The *Err fields make the matching methods fail. Lock Mu to change the fields concurrently.
+type Node struct {
+	Mu sync.Mutex
+
+	Options  *node.Options
+	Overlay  common.Address
+	Mode     api.BeeNodeMode
+	Peers    int
+	Kademlia node.Topology
+
+	Account    node.Wallet
+	Chequebook *big.Int
+	Chain      node.ChainState
+	Stamps     []node.Batch
+	Accounting node.Settlements
+
+	Files  map[string]File
+	Pinned map[string]bool
+	// Transactions are the hashes of the transactions sent, in order.
+	Transactions []common.Hash
+	// Shutdowns is the number of calls to Shutdown.
+	Shutdowns int
+
+	StartErr      error
+	HealthErr     error
+	UploadErr     error
+	DownloadErr   error
+	ChequebookErr error
+	ChainStateErr error
+	WalletErr     error
+	TxErr         error
+
+	started bool
+}
+
+// New returns a stopped fake node in ultra-light mode with an empty wallet.
+func New() *Node {
+	return &Node{
+		Mode:       api.UltraLightMode,
+		Account:    node.Wallet{BZZ: new(big.Int), NativeToken: new(big.Int)},
+		Chequebook: new(big.Int),
+		Chain:      node.ChainState{CurrentPrice: new(big.Int), TotalAmount: new(big.Int)},
+		Files:      map[string]File{},
+		Pinned:     map[string]bool{},
+	}
+}
+
+func (n *Node) Start(o *node.Options) error {
+	n.Mu.Lock()
+	defer n.Mu.Unlock()
+	if n.StartErr != nil {
+		return n.StartErr
+	}
+	if n.started {
+		return node.ErrAlreadyStarted
+	}
+	n.started = true
+	n.Options = o
+	if o.SwapEnable {
+		n.Mode = api.LightMode
+	}
+	return nil
+}
+
+func (n *Node) Shutdown(context.Context) error {
+	n.Mu.Lock()
+	defer n.Mu.Unlock()
+	n.Shutdowns++
+	if !n.started {
+		return node.ErrNotStarted
+	}
+	n.started = false
+	return nil
+}
+
+// Started reports whether the node was started and not shut down.
+func (n *Node) Started() bool {
+	n.Mu.Lock()
+	defer n.Mu.Unlock()
+	return n.started
+}
+
+func (n *Node) OverlayEthAddress() common.Address {
+	n.Mu.Lock()
+	defer n.Mu.Unlock()
+	return n.Overlay
+}
+
+func (n *Node) BeeNodeMode() api.BeeNodeMode {
+	n.Mu.Lock()
+	defer n.Mu.Unlock()
+	if !n.started {
+		return api.UnknownMode
+	}
+	return n.Mode
+}
+
+func (n *Node) ConnectedPeerCount() int {
+	n.Mu.Lock()
+	defer n.Mu.Unlock()
+	return n.Peers
+}
+
+func (n *Node) Health(context.Context) error {
+	n.Mu.Lock()
+	defer n.Mu.Unlock()
+	if !n.started {
+		return node.ErrNotStarted
+	}
+	return n.HealthErr
+}
+
+func (n *Node) AddFileBzz(_ context.Context, batchID, filename, mimetype string, encrypt bool, reader io.Reader) (swarm.Address, error) {
+	return n.add(File{Name: filename, Mimetype: mimetype, BatchID: batchID, Encrypt: encrypt}, reader)
+}
+
+func (n *Node) AddDirBzz(_ context.Context, batchID, name, indexDocument, errorDocument string, encrypt bool, tarReader io.Reader) (swarm.Address, error) {
+	return n.add(File{Name: name, Mimetype: node.ContentTypeTar, BatchID: batchID, Encrypt: encrypt, IndexDocument: indexDocument, ErrorDocument: errorDocument}, tarReader)
+}
+
+// add keeps the upload under the hash of its content.
+func (n *Node) add(f File, r io.Reader) (swarm.Address, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return swarm.ZeroAddress, err
+	}
+	n.Mu.Lock()
+	defer n.Mu.Unlock()
+	if n.UploadErr != nil {
+		return swarm.ZeroAddress, n.UploadErr
+	}
+	f.Data = data
+	sum := sha256.Sum256(data)
+	address := swarm.NewAddress(sum[:])
+	n.Files[address.String()] = f
+	return address, nil
+}
+
+func (n *Node) GetBzz(_ context.Context, address swarm.Address) (io.Reader, string, error) {
+	n.Mu.Lock()
+	defer n.Mu.Unlock()
+	if n.DownloadErr != nil {
+		return nil, "", n.DownloadErr
+	}
+	f, ok := n.Files[address.String()]
+	if !ok {
+		return nil, "", node.ErrNotFound
+	}
+	return bytes.NewReader(f.Data), f.Name, nil
+}
+
+// GetUsableBatches returns the stamp issuers of the usable batches.
+func (n *Node) GetUsableBatches() []*postage.StampIssuer {
+	n.Mu.Lock()
+	defer n.Mu.Unlock()
+	issuers := []*postage.StampIssuer{}
+	for _, b := range n.Stamps {
+		if b.Usable {
+			issuers = append(issuers, postage.NewStampIssuer(b.Label, "", common.FromHex(b.ID), b.Amount, b.Depth, b.BucketDepth, 0, b.Immutable))
+		}
+	}
+	return issuers
+}
+
+// BuyStamp adds a usable batch and takes its cost from the xBZZ balance of the wallet.
+func (n *Node) BuyStamp(amount *big.Int, depth uint64, label string, immutable bool) (common.Hash, []byte, error) {
+	n.Mu.Lock()
+	defer n.Mu.Unlock()
+	cost := node.BatchCost(amount, uint8(depth))
+	if n.Account.BZZ.Cmp(cost) < 0 {
+		return common.Hash{}, nil, fmt.Errorf("insufficient xBZZ balance")
+	}
+	tx, err := n.transaction()
+	if err != nil {
+		return common.Hash{}, nil, err
+	}
+	n.Account.BZZ = new(big.Int).Sub(n.Account.BZZ, cost)
+	id := tx.Bytes()
+	n.Stamps = append(n.Stamps, node.Batch{
+		ID:          common.Bytes2Hex(id),
+		Label:       label,
+		Depth:       uint8(depth),
+		BucketDepth: node.MinBatchDepth - 1,
+		Amount:      amount,
+		Immutable:   immutable,
+		Usable:      true,
+	})
+	return tx, id, nil
+}
+
+func (n *Node) ChequebookBalance() (*big.Int, error) {
+	n.Mu.Lock()
+	defer n.Mu.Unlock()
+	if n.ChequebookErr != nil {
+		return nil, n.ChequebookErr
+	}
+	return n.Chequebook, nil
+}
+
+// ChequebookDeposit moves amount from the xBZZ balance of the wallet to the chequebook.
+func (n *Node) ChequebookDeposit(_ context.Context, amount *big.Int) (common.Hash, error) {
+	n.Mu.Lock()
+	defer n.Mu.Unlock()
+	if n.Account.BZZ.Cmp(amount) < 0 {
+		return common.Hash{}, fmt.Errorf("insufficient xBZZ balance")
+	}
+	tx, err := n.transaction()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	n.Account.BZZ = new(big.Int).Sub(n.Account.BZZ, amount)
+	n.Chequebook = new(big.Int).Add(n.Chequebook, amount)
+	return tx, nil
+}
+
+// ChequebookWithdraw moves amount from the chequebook to the xBZZ balance of the wallet.
+func (n *Node) ChequebookWithdraw(_ context.Context, amount *big.Int) (common.Hash, error) {
+	n.Mu.Lock()
+	defer n.Mu.Unlock()
+	if n.Chequebook.Cmp(amount) < 0 {
+		return common.Hash{}, fmt.Errorf("insufficient chequebook balance")
+	}
+	tx, err := n.transaction()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	n.Chequebook = new(big.Int).Sub(n.Chequebook, amount)
+	n.Account.BZZ = new(big.Int).Add(n.Account.BZZ, amount)
+	return tx, nil
+}
+
+// WaitForReceipt returns a successful receipt for the transactions sent by the node.
+func (n *Node) WaitForReceipt(_ context.Context, txHash common.Hash) (*types.Receipt, error) {
+	n.Mu.Lock()
+	defer n.Mu.Unlock()
+	for _, tx := range n.Transactions {
+		if tx == txHash {
+			return &types.Receipt{TxHash: txHash, Status: types.ReceiptStatusSuccessful}, nil
+		}
+	}
+	return nil, node.ErrNotFound
+}
+
+func (n *Node) Settlements(context.Context) (node.Settlements, error) {
+	n.Mu.Lock()
+	defer n.Mu.Unlock()
+	return n.Accounting, nil
+}
+
+func (n *Node) CashOut(context.Context, string) (common.Hash, error) {
+	n.Mu.Lock()
+	defer n.Mu.Unlock()
+	return n.transaction()
+}
+
+func (n *Node) Topology(context.Context) (node.Topology, error) {
+	n.Mu.Lock()
+	defer n.Mu.Unlock()
+	return n.Kademlia, nil
+}
+
+func (n *Node) Batches(context.Context) ([]node.Batch, error) {
+	n.Mu.Lock()
+	defer n.Mu.Unlock()
+	return append([]node.Batch(nil), n.Stamps...), nil
+}
+
+func (n *Node) ChainState(context.Context) (node.ChainState, error) {
+	n.Mu.Lock()
+	defer n.Mu.Unlock()
+	return n.Chain, n.ChainStateErr
+}
+
+func (n *Node) Wallet(context.Context) (node.Wallet, error) {
+	n.Mu.Lock()
+	defer n.Mu.Unlock()
+	return n.Account, n.WalletErr
+}
+
+func (n *Node) TopUpBatch(_ context.Context, batchID string, amount *big.Int) (common.Hash, error) {
+	n.Mu.Lock()
+	defer n.Mu.Unlock()
+	b, err := n.batch(batchID)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	tx, err := n.transaction()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	b.Amount = new(big.Int).Add(b.Amount, amount)
+	return tx, nil
+}
+
+func (n *Node) DiluteBatch(_ context.Context, batchID string, depth uint8) (common.Hash, error) {
+	n.Mu.Lock()
+	defer n.Mu.Unlock()
+	b, err := n.batch(batchID)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if depth <= b.Depth {
+		return common.Hash{}, fmt.Errorf("depth %d is not larger than %d", depth, b.Depth)
+	}
+	tx, err := n.transaction()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	b.Depth = depth
+	return tx, nil
+}
+
+func (n *Node) Pin(_ context.Context, address swarm.Address) error {
+	n.Mu.Lock()
+	defer n.Mu.Unlock()
+	if _, ok := n.Files[address.String()]; !ok {
+		return node.ErrNotFound
+	}
+	n.Pinned[address.String()] = true
+	return nil
+}
+
+func (n *Node) Unpin(_ context.Context, address swarm.Address) error {
+	n.Mu.Lock()
+	defer n.Mu.Unlock()
+	if !n.Pinned[address.String()] {
+		return node.ErrNotFound
+	}
+	delete(n.Pinned, address.String())
+	return nil
+}
+
+func (n *Node) IsPinned(_ context.Context, address swarm.Address) (bool, error) {
+	n.Mu.Lock()
+	defer n.Mu.Unlock()
+	return n.Pinned[address.String()], nil
+}
+
+func (n *Node) Pins(context.Context) ([]swarm.Address, error) {
+	n.Mu.Lock()
+	defer n.Mu.Unlock()
+	pins := []swarm.Address{}
+	for ref := range n.Pinned {
+		address, err := swarm.ParseHexAddress(ref)
+		if err != nil {
+			return nil, err
+		}
+		pins = append(pins, address)
+	}
+	return pins, nil
+}
+
+// CheckPins reports every pin as complete and valid.
+func (n *Node) CheckPins(_ context.Context, address swarm.Address, fn func(node.PinStat)) error {
+	pins, err := n.Pins(context.Background())
+	if err != nil {
+		return err
+	}
+	for _, pin := range pins {
+		if !address.IsZero() && !address.Equal(pin) {
+			continue
+		}
+		fn(node.PinStat{Reference: pin, Total: 1})
+	}
+	return nil
+}
+
+// transaction records a new transaction hash, or fails with TxErr. Mu must be held.
+func (n *Node) transaction() (common.Hash, error) {
+	if n.TxErr != nil {
+		return common.Hash{}, n.TxErr
+	}
+	tx := common.BigToHash(big.NewInt(int64(len(n.Transactions) + 1)))
+	n.Transactions = append(n.Transactions, tx)
+	return tx, nil
+}
+
+// batch returns the batch with the ID. Mu must be held.
+func (n *Node) batch(id string) (*node.Batch, error) {
+	for k := range n.Stamps {
+		if n.Stamps[k].ID == id {
+			return &n.Stamps[k], nil
+		}
+	}
+	return nil, node.ErrNotFound
+}
@@ -0,0 +1,69 @@
+package node
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/ethersphere/bee/v2/pkg/api"
+	"github.com/ethersphere/bee/v2/pkg/swarm"
+)
+
+// PinStat is the result of an integrity check of a pinned reference.
+type PinStat = api.PinIntegrityResponse
+
+// Pin keeps the content of the reference in the local store, retrieving the missing chunks from the network.
+func (n *beeNode) Pin(ctx context.Context, address swarm.Address) error {
+	return n.request(ctx, http.MethodPost, "/pins/"+address.String(), nil)
+}
+
+func (n *beeNode) Unpin(ctx context.Context, address swarm.Address) error {
+	return n.request(ctx, http.MethodDelete, "/pins/"+address.String(), nil)
+}
+
+func (n *beeNode) IsPinned(ctx context.Context, address swarm.Address) (bool, error) {
+	err := n.request(ctx, http.MethodGet, "/pins/"+address.String(), nil)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (n *beeNode) Pins(ctx context.Context) ([]swarm.Address, error) {
+	resp := struct {
+		References []swarm.Address `json:"references"`
+	}{}
+	if err := n.request(ctx, http.MethodGet, "/pins", &resp); err != nil {
+		return nil, err
+	}
+	return resp.References, nil
+}
+
+// CheckPins walks the chunks of the pinned reference, or of every pin if address is the zero address,
+// and calls fn with the chunk counts of each pin as soon as it is checked.
+func (n *beeNode) CheckPins(ctx context.Context, address swarm.Address, fn func(PinStat)) error {
+	path := "/pins/check"
+	if !address.IsZero() {
+		path += "?ref=" + address.String()
+	}
+	resp, err := n.do(ctx, http.MethodGet, path)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	dec := json.NewDecoder(resp.Body)
+	for {
+		var stat PinStat
+		err := dec.Decode(&stat)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		fn(stat)
+	}
+}
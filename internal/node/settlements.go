@@ -0,0 +1,145 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethersphere/bee/v2/pkg/bigint"
+)
+
+// PeerSettlement is the accounting state of the node with a peer. Amounts are in PLUR.
+type PeerSettlement struct {
+	Peer string
+	// Balance is positive when the peer owes the node and negative when the node owes the peer.
+	Balance *big.Int
+	// Received and Sent are the amounts settled with SWAP cheques.
+	Received *big.Int
+	Sent     *big.Int
+	// LastReceivedCheque and LastSentCheque are the cumulative payouts of the last cheques, nil if there is none.
+	LastReceivedCheque *big.Int
+	LastSentCheque     *big.Int
+	// Uncashed is the amount of the received cheques that is not cashed out yet.
+	Uncashed *big.Int
+}
+
+// Settlements is the accounting state of the node with all its peers.
+type Settlements struct {
+	Peers         []PeerSettlement
+	TotalBalance  *big.Int
+	TotalReceived *big.Int
+	TotalSent     *big.Int
+	TotalUncashed *big.Int
+}
+
+type chequeResponse struct {
+	Payout *bigint.BigInt `json:"payout"`
+}
+
+// Settlements merges the balances, the settlements and the last cheques of every peer,
+// and looks up the uncashed amount of the peers that sent a cheque.
+func (n *beeNode) Settlements(ctx context.Context) (Settlements, error) {
+	balances := struct {
+		Balances []struct {
+			Peer    string         `json:"peer"`
+			Balance *bigint.BigInt `json:"balance"`
+		} `json:"balances"`
+	}{}
+	if err := n.request(ctx, http.MethodGet, "/balances", &balances); err != nil {
+		return Settlements{}, err
+	}
+	settlements := struct {
+		TotalReceived *bigint.BigInt `json:"totalReceived"`
+		TotalSent     *bigint.BigInt `json:"totalSent"`
+		Settlements   []struct {
+			Peer     string         `json:"peer"`
+			Received *bigint.BigInt `json:"received"`
+			Sent     *bigint.BigInt `json:"sent"`
+		} `json:"settlements"`
+	}{}
+	if err := n.request(ctx, http.MethodGet, "/settlements", &settlements); err != nil {
+		return Settlements{}, err
+	}
+	cheques := struct {
+		LastCheques []struct {
+			Peer         string          `json:"peer"`
+			LastReceived *chequeResponse `json:"lastreceived"`
+			LastSent     *chequeResponse `json:"lastsent"`
+		} `json:"lastcheques"`
+	}{}
+	if err := n.request(ctx, http.MethodGet, "/chequebook/cheque", &cheques); err != nil {
+		return Settlements{}, err
+	}
+
+	peers := map[string]*PeerSettlement{}
+	peer := func(address string) *PeerSettlement {
+		p, ok := peers[address]
+		if !ok {
+			p = &PeerSettlement{Peer: address, Balance: new(big.Int), Received: new(big.Int), Sent: new(big.Int), Uncashed: new(big.Int)}
+			peers[address] = p
+		}
+		return p
+	}
+	for _, b := range balances.Balances {
+		peer(b.Peer).Balance = bigOrZero(b.Balance)
+	}
+	for _, s := range settlements.Settlements {
+		p := peer(s.Peer)
+		p.Received = bigOrZero(s.Received)
+		p.Sent = bigOrZero(s.Sent)
+	}
+	for _, c := range cheques.LastCheques {
+		p := peer(c.Peer)
+		if c.LastReceived != nil {
+			p.LastReceivedCheque = bigOrZero(c.LastReceived.Payout)
+		}
+		if c.LastSent != nil {
+			p.LastSentCheque = bigOrZero(c.LastSent.Payout)
+		}
+	}
+
+	result := Settlements{
+		TotalBalance:  new(big.Int),
+		TotalReceived: bigOrZero(settlements.TotalReceived),
+		TotalSent:     bigOrZero(settlements.TotalSent),
+		TotalUncashed: new(big.Int),
+	}
+	for _, p := range peers {
+		if p.LastReceivedCheque != nil {
+			uncashed, err := n.uncashed(ctx, p.Peer)
+			if err != nil {
+				return Settlements{}, err
+			}
+			p.Uncashed = uncashed
+		}
+		result.TotalBalance.Add(result.TotalBalance, p.Balance)
+		result.TotalUncashed.Add(result.TotalUncashed, p.Uncashed)
+		result.Peers = append(result.Peers, *p)
+	}
+	sort.Slice(result.Peers, func(a, b int) bool {
+		return result.Peers[a].Peer < result.Peers[b].Peer
+	})
+	return result, nil
+}
+
+func (n *beeNode) uncashed(ctx context.Context, peer string) (*big.Int, error) {
+	status := struct {
+		UncashedAmount *bigint.BigInt `json:"uncashedAmount"`
+	}{}
+	err := n.request(ctx, http.MethodGet, "/chequebook/cashout/"+peer, &status)
+	if errors.Is(err, ErrNotFound) {
+		return new(big.Int), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return bigOrZero(status.UncashedAmount), nil
+}
+
+// CashOut cashes the last cheque received from the peer. It returns as soon as the transaction is sent.
+func (n *beeNode) CashOut(ctx context.Context, peer string) (common.Hash, error) {
+	return n.chequebookTransaction(ctx, "/chequebook/cashout/"+peer)
+}
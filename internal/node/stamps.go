@@ -0,0 +1,195 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethersphere/bee/v2/pkg/bigint"
+)
+
+// MinBatchDepth is the smallest depth of a batch, one more than the bucket depth.
+const MinBatchDepth = 17
+
+// Batch is a postage batch owned by the node.
+type Batch struct {
+	ID          string
+	Label       string
+	Depth       uint8
+	BucketDepth uint8
+	// Utilization is the fill of the fullest bucket.
+	Utilization uint32
+	// Amount is the value per chunk paid for the batch, in PLUR.
+	Amount    *big.Int
+	Immutable bool
+	Usable    bool
+	// TTL is the remaining time to live of the batch at the current price.
+	TTL time.Duration
+}
+
+// BucketCapacity returns the number of chunks a bucket of the batch can hold.
+func (b Batch) BucketCapacity() uint32 {
+	return 1 << (b.Depth - b.BucketDepth)
+}
+
+// UtilizationPercent returns how full the fullest bucket of the batch is.
+func (b Batch) UtilizationPercent() float64 {
+	return float64(b.Utilization) / float64(b.BucketCapacity()) * 100
+}
+
+// ChainState is the state of the postage contract.
+type ChainState struct {
+	Block uint64
+	// CurrentPrice is the price of storing a chunk for a block, in PLUR.
+	CurrentPrice *big.Int
+	// TotalAmount is the cumulative amount paid per chunk since the contract was deployed, in PLUR.
+	TotalAmount *big.Int
+}
+
+// TTL returns how long an amount per chunk pays for storage at the current price.
+func (s ChainState) TTL(amount *big.Int, blockTime time.Duration) time.Duration {
+	if s.CurrentPrice == nil || s.CurrentPrice.Sign() <= 0 {
+		return 0
+	}
+	blocks := new(big.Int).Quo(amount, s.CurrentPrice)
+	return time.Duration(blocks.Int64()) * blockTime
+}
+
+// Amount returns the amount per chunk that pays for storage for ttl at the current price.
+func (s ChainState) Amount(ttl, blockTime time.Duration) *big.Int {
+	blocks := int64((ttl + blockTime - 1) / blockTime)
+	return new(big.Int).Mul(s.CurrentPrice, big.NewInt(blocks))
+}
+
+// effectiveVolumes is the data a batch of depth MinBatchDepth+n can store in bytes, unencrypted and encrypted,
+// without erasure coding. The chunk addresses do not fill the buckets evenly, so a batch is full long before
+// 2^depth chunks. The values are the effective utilisation table published in the Swarm documentation.
+var effectiveVolumes = [...][2]int64{
+	{44_700, 44_350},                         // 17
+	{6_660_000, 6_610_000},                   // 18
+	{112_060_000, 111_180_000},               // 19
+	{687_620_000, 682_210_000},               // 20
+	{2_600_000_000, 2_580_000_000},           // 21
+	{7_730_000_000, 7_670_000_000},           // 22
+	{19_940_000_000, 19_780_000_000},         // 23
+	{47_060_000_000, 46_690_000_000},         // 24
+	{105_510_000_000, 104_680_000_000},       // 25
+	{227_980_000_000, 226_190_000_000},       // 26
+	{476_680_000_000, 472_930_000_000},       // 27
+	{993_650_000_000, 985_830_000_000},       // 28
+	{2_040_000_000_000, 2_020_000_000_000},   // 29
+	{4_170_000_000_000, 4_140_000_000_000},   // 30
+	{8_450_000_000_000, 8_390_000_000_000},   // 31
+	{17_110_000_000_000, 16_980_000_000_000}, // 32
+	{34_560_000_000_000, 34_290_000_000_000}, // 33
+	{69_620_000_000_000, 69_080_000_000_000}, // 34
+}
+
+// MaxBatchDepth is the largest depth with a known effective volume.
+const MaxBatchDepth uint8 = MinBatchDepth + uint8(len(effectiveVolumes)) - 1
+
+// EffectiveVolume returns how many bytes a batch of the given depth can store in practice.
+func EffectiveVolume(depth uint8, encrypted bool) int64 {
+	if depth < MinBatchDepth {
+		return 0
+	}
+	if depth > MaxBatchDepth {
+		depth = MaxBatchDepth
+	}
+	volume := effectiveVolumes[depth-MinBatchDepth]
+	if encrypted {
+		return volume[1]
+	}
+	return volume[0]
+}
+
+// DepthForCapacity returns the depth of the smallest batch whose effective volume holds size bytes.
+func DepthForCapacity(size int64, encrypted bool) (uint8, error) {
+	for depth := uint8(MinBatchDepth); depth <= MaxBatchDepth; depth++ {
+		if EffectiveVolume(depth, encrypted) >= size {
+			return depth, nil
+		}
+	}
+	return 0, fmt.Errorf("capacity larger than the biggest batch of %d bytes", EffectiveVolume(MaxBatchDepth, encrypted))
+}
+
+// BatchCost returns the price in PLUR of a batch of the given depth and amount per chunk.
+func BatchCost(amount *big.Int, depth uint8) *big.Int {
+	return new(big.Int).Lsh(amount, uint(depth))
+}
+
+func (n *beeNode) Batches(ctx context.Context) ([]Batch, error) {
+	resp := struct {
+		Stamps []struct {
+			BatchID       string         `json:"batchID"`
+			Utilization   uint32         `json:"utilization"`
+			Usable        bool           `json:"usable"`
+			Label         string         `json:"label"`
+			Depth         uint8          `json:"depth"`
+			Amount        *bigint.BigInt `json:"amount"`
+			BucketDepth   uint8          `json:"bucketDepth"`
+			ImmutableFlag bool           `json:"immutableFlag"`
+			BatchTTL      int64          `json:"batchTTL"`
+		} `json:"stamps"`
+	}{}
+	if err := n.request(ctx, http.MethodGet, "/stamps", &resp); err != nil {
+		return nil, err
+	}
+
+	batches := make([]Batch, 0, len(resp.Stamps))
+	for _, s := range resp.Stamps {
+		batches = append(batches, Batch{
+			ID:          s.BatchID,
+			Label:       s.Label,
+			Depth:       s.Depth,
+			BucketDepth: s.BucketDepth,
+			Utilization: s.Utilization,
+			Amount:      bigOrZero(s.Amount),
+			Immutable:   s.ImmutableFlag,
+			Usable:      s.Usable,
+			TTL:         time.Duration(s.BatchTTL) * time.Second,
+		})
+	}
+	return batches, nil
+}
+
+func (n *beeNode) ChainState(ctx context.Context) (ChainState, error) {
+	resp := struct {
+		Block        uint64         `json:"block"`
+		TotalAmount  *bigint.BigInt `json:"totalAmount"`
+		CurrentPrice *bigint.BigInt `json:"currentPrice"`
+	}{}
+	if err := n.request(ctx, http.MethodGet, "/chainstate", &resp); err != nil {
+		return ChainState{}, err
+	}
+
+	return ChainState{
+		Block:        resp.Block,
+		CurrentPrice: bigOrZero(resp.CurrentPrice),
+		TotalAmount:  bigOrZero(resp.TotalAmount),
+	}, nil
+}
+
+// TopUpBatch adds amount per chunk to the batch, extending its TTL. It returns when the transaction is mined.
+func (n *beeNode) TopUpBatch(ctx context.Context, batchID string, amount *big.Int) (common.Hash, error) {
+	return n.batchTransaction(ctx, fmt.Sprintf("/stamps/topup/%s/%s", batchID, amount.String()))
+}
+
+// DiluteBatch increases the depth of the batch, doubling its capacity and halving its TTL for every level.
+// It returns when the transaction is mined.
+func (n *beeNode) DiluteBatch(ctx context.Context, batchID string, depth uint8) (common.Hash, error) {
+	return n.batchTransaction(ctx, fmt.Sprintf("/stamps/dilute/%s/%d", batchID, depth))
+}
+
+func (n *beeNode) batchTransaction(ctx context.Context, path string) (common.Hash, error) {
+	resp := struct {
+		TxHash string `json:"txHash"`
+	}{}
+	if err := n.request(ctx, http.MethodPatch, path, &resp); err != nil {
+		return common.Hash{}, err
+	}
+	return common.HexToHash(resp.TxHash), nil
+}
@@ -0,0 +1,56 @@
+package node
+
+import "testing"
+
+func TestDepthForCapacity(t *testing.T) {
+	const (
+		mb = 1 << 20
+		gb = 1 << 30
+	)
+	tests := []struct {
+		name      string
+		size      int64
+		encrypted bool
+		depth     uint8
+	}{
+		{"1 MB", 1 * mb, false, 18},
+		{"6 MB", 6 * mb, false, 18},
+		{"10 MB", 10 * mb, false, 19},
+		{"100 MB", 100 * mb, false, 19},
+		{"100 MB encrypted", 100 * mb, true, 19},
+		{"106.2 MB", 1062 * mb / 10, false, 19},
+		{"106.2 MB encrypted", 1062 * mb / 10, true, 20},
+		{"500 MB", 500 * mb, false, 20},
+		{"1 GB", 1 * gb, false, 21},
+		{"2.5 GB encrypted", 5 * gb / 2, true, 22},
+		{"5 GB", 5 * gb, false, 22},
+		{"7.15 GB", 715 * gb / 100, false, 22},
+		{"7.15 GB encrypted", 715 * gb / 100, true, 23},
+		{"10 GB", 10 * gb, false, 23},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			depth, err := DepthForCapacity(tt.size, tt.encrypted)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if depth != tt.depth {
+				t.Errorf("got depth %d, want %d", depth, tt.depth)
+			}
+			if volume := EffectiveVolume(depth, tt.encrypted); volume < tt.size {
+				t.Errorf("depth %d holds %d bytes, less than %d", depth, volume, tt.size)
+			}
+			if depth > MinBatchDepth {
+				if volume := EffectiveVolume(depth-1, tt.encrypted); volume >= tt.size {
+					t.Errorf("depth %d already holds %d bytes", depth-1, tt.size)
+				}
+			}
+		})
+	}
+}
+
+func TestDepthForCapacityTooLarge(t *testing.T) {
+	if _, err := DepthForCapacity(EffectiveVolume(MaxBatchDepth, false)+1, false); err == nil {
+		t.Fatal("expected an error for a capacity above the biggest batch")
+	}
+}
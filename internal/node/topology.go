@@ -0,0 +1,132 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethersphere/bee/v2/pkg/swarm"
+	"github.com/ethersphere/bee/v2/pkg/topology"
+)
+
+// Peer is a connected peer of the node.
+type Peer struct {
+	Address string
+	// Bin is the proximity order of the peer to the overlay of the node.
+	Bin      uint8
+	FullNode bool
+	// Latency is the moving average of the ping round trip, zero if it is not measured yet.
+	Latency time.Duration
+	// Direction is inbound or outbound, empty if it is unknown.
+	Direction    string
+	Reachability string
+	Healthy      bool
+	// ConnectedFor is the duration of the current session with the peer.
+	ConnectedFor time.Duration
+}
+
+// Bin is the number of known and connected full node peers at a proximity order.
+type Bin struct {
+	Population int
+	Connected  int
+}
+
+// Topology is the kademlia table of the node. The API of the node does not expose
+// the underlay addresses of the peers, only the ones of the node itself.
+type Topology struct {
+	Overlay             string
+	Underlay            []string
+	Depth               uint8
+	Population          int
+	Connected           int
+	Reachability        string
+	NetworkAvailability string
+	// Bins is indexed by proximity order.
+	Bins []Bin
+	// Peers are the connected full and light node peers, sorted by bin and address.
+	Peers []Peer
+}
+
+// Topology reads the kademlia table, the connected light nodes and the underlay addresses of the node.
+func (n *beeNode) Topology(ctx context.Context) (Topology, error) {
+	resp := struct {
+		Base                string                      `json:"baseAddr"`
+		Population          int                         `json:"population"`
+		Connected           int                         `json:"connected"`
+		Depth               uint8                       `json:"depth"`
+		Reachability        string                      `json:"reachability"`
+		NetworkAvailability string                      `json:"networkAvailability"`
+		Bins                map[string]topology.BinInfo `json:"bins"`
+		LightNodes          topology.BinInfo            `json:"lightNodes"`
+	}{}
+	if err := n.request(ctx, http.MethodGet, "/topology", &resp); err != nil {
+		return Topology{}, err
+	}
+	addresses := struct {
+		Underlay []string `json:"underlay"`
+	}{}
+	if err := n.request(ctx, http.MethodGet, "/addresses", &addresses); err != nil {
+		return Topology{}, err
+	}
+
+	t := Topology{
+		Overlay:             resp.Base,
+		Underlay:            addresses.Underlay,
+		Depth:               resp.Depth,
+		Population:          resp.Population,
+		Connected:           resp.Connected,
+		Reachability:        resp.Reachability,
+		NetworkAvailability: resp.NetworkAvailability,
+		Bins:                make([]Bin, swarm.MaxBins),
+	}
+	for name, info := range resp.Bins {
+		bin, err := strconv.Atoi(strings.TrimPrefix(name, "bin_"))
+		if err != nil || bin < 0 || bin >= len(t.Bins) {
+			return Topology{}, fmt.Errorf("unexpected topology bin %q", name)
+		}
+		t.Bins[bin] = Bin{Population: int(info.BinPopulation), Connected: int(info.BinConnected)}
+		for _, p := range info.ConnectedPeers {
+			t.Peers = append(t.Peers, newPeer(p, uint8(bin), true))
+		}
+	}
+	base, err := swarm.ParseHexAddress(resp.Base)
+	if err != nil {
+		return Topology{}, fmt.Errorf("overlay address: %w", err)
+	}
+	for _, p := range resp.LightNodes.ConnectedPeers {
+		t.Peers = append(t.Peers, newPeer(p, swarm.Proximity(base.Bytes(), p.Address.Bytes()), false))
+	}
+
+	// trim the empty bins beyond the last populated one
+	last := 0
+	for i, b := range t.Bins {
+		if b.Population > 0 {
+			last = i
+		}
+	}
+	t.Bins = t.Bins[:last+1]
+
+	sort.Slice(t.Peers, func(a, b int) bool {
+		if t.Peers[a].Bin != t.Peers[b].Bin {
+			return t.Peers[a].Bin < t.Peers[b].Bin
+		}
+		return t.Peers[a].Address < t.Peers[b].Address
+	})
+	return t, nil
+}
+
+func newPeer(info *topology.PeerInfo, bin uint8, fullNode bool) Peer {
+	p := Peer{Address: info.Address.String(), Bin: bin, FullNode: fullNode}
+	if m := info.Metrics; m != nil {
+		p.Latency = time.Duration(m.LatencyEWMA) * time.Millisecond
+		p.Direction = m.SessionConnectionDirection
+		p.Reachability = m.Reachability
+		p.Healthy = m.Healthy
+		p.ConnectedFor = time.Duration(m.SessionConnectionDuration * float64(time.Second))
+	}
+	return p
+}
@@ -0,0 +1,42 @@
+package node
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethersphere/bee/v2/pkg/bigint"
+)
+
+// Wallet holds the balances of the node's Ethereum address.
+type Wallet struct {
+	Address common.Address
+	// BZZ is the xBZZ balance in PLUR.
+	BZZ *big.Int
+	// NativeToken is the xDAI balance in wei.
+	NativeToken *big.Int
+	ChainID     int64
+	Chequebook  common.Address
+}
+
+func (n *beeNode) Wallet(ctx context.Context) (Wallet, error) {
+	resp := struct {
+		BZZ                       *bigint.BigInt `json:"bzzBalance"`
+		NativeToken               *bigint.BigInt `json:"nativeTokenBalance"`
+		ChainID                   int64          `json:"chainID"`
+		ChequebookContractAddress common.Address `json:"chequebookContractAddress"`
+		WalletAddress             common.Address `json:"walletAddress"`
+	}{}
+	if err := n.request(ctx, http.MethodGet, "/wallet", &resp); err != nil {
+		return Wallet{}, err
+	}
+
+	return Wallet{
+		Address:     resp.WalletAddress,
+		BZZ:         bigOrZero(resp.BZZ),
+		NativeToken: bigOrZero(resp.NativeToken),
+		ChainID:     resp.ChainID,
+		Chequebook:  resp.ChequebookContractAddress,
+	}, nil
+}
@@ -0,0 +1,485 @@
+package screens
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"image/color"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/Solar-Punk-Ltd/swarm-mobile/internal/applock"
+)
+
+const (
+	lockTimeoutPrefKey = "lockTimeout"
+	defaultLockTimeout = 5 * time.Minute
+	lockTimeoutNever   = -1
+	// instrumentInterval is how often the widgets shown since the last pass get their callbacks wrapped.
+	instrumentInterval = time.Second
+)
+
+var lockTimeouts = []struct {
+	label   string
+	timeout time.Duration
+}{
+	{"Immediately", 0},
+	{"After 1 minute", time.Minute},
+	{"After 5 minutes", 5 * time.Minute},
+	{"After 15 minutes", 15 * time.Minute},
+	{"Never", lockTimeoutNever},
+}
+
+// appLock hides the app behind a PIN screen on launch and once the app was idle, in the foreground
+// or in the background, for longer than the timeout.
+type appLock struct {
+	*index
+	pin             *applock.Lock
+	content         fyne.CanvasObject
+	screen          fyne.CanvasObject
+	pinEntry        *widget.Entry
+	backgroundSince time.Time
+	lastActivity    time.Time
+	idleTimer       *time.Timer
+	// instrumented are the widgets whose callbacks record the activity.
+	instrumented map[fyne.CanvasObject]bool
+}
+
+// activityDetector fills the window behind the content. Fyne does not report input globally, it gets
+// the taps, drags and mouse moves that no widget of the content handles. The input handled by the
+// widgets is recorded by their callbacks, see instrument.
+type activityDetector struct {
+	widget.BaseWidget
+	onActivity func()
+}
+
+func newActivityDetector(onActivity func()) *activityDetector {
+	a := &activityDetector{onActivity: onActivity}
+	a.ExtendBaseWidget(a)
+	return a
+}
+
+func (a *activityDetector) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(canvas.NewRectangle(color.Transparent))
+}
+
+func (a *activityDetector) Tapped(*fyne.PointEvent)          { a.onActivity() }
+func (a *activityDetector) TappedSecondary(*fyne.PointEvent) { a.onActivity() }
+func (a *activityDetector) Dragged(*fyne.DragEvent)          { a.onActivity() }
+func (a *activityDetector) DragEnd()                         { a.onActivity() }
+func (a *activityDetector) MouseIn(*desktop.MouseEvent)      { a.onActivity() }
+func (a *activityDetector) MouseMoved(*desktop.MouseEvent)   { a.onActivity() }
+func (a *activityDetector) MouseOut()                        {}
+
+// newAppLock wraps the content of the window with the lock screen, locked if a PIN is set.
+func (i *index) newAppLock(content fyne.CanvasObject) fyne.CanvasObject {
+	l := &appLock{index: i, content: content}
+	if !i.nodeConfig.isKeyStoreMem {
+		pin, err := applock.Open(i.rootPath)
+		if err != nil {
+			i.logger.Log(fmt.Sprintf("%s, app lock disabled", err.Error()))
+		}
+		l.pin = pin
+	}
+	i.appLock = l
+
+	l.pinEntry = widget.NewPasswordEntry()
+	l.pinEntry.SetPlaceHolder("PIN")
+	l.pinEntry.OnSubmitted = func(string) { l.unlock() }
+	unlockButton := widget.NewButton("Unlock", l.unlock)
+	unlockButton.Importance = widget.HighImportance
+	title := widget.NewLabel("Swarm Mobile is locked")
+	title.TextStyle.Bold = true
+	l.screen = container.NewStack(
+		canvas.NewRectangle(theme.Color(theme.ColorNameBackground)),
+		container.NewVBox(title, l.pinEntry, unlockButton),
+	)
+	l.screen.Hide()
+
+	lifecycle := i.app.Lifecycle()
+	lifecycle.SetOnExitedForeground(func() {
+		l.backgroundSince = time.Now()
+	})
+	lifecycle.SetOnEnteredForeground(func() {
+		timeout := l.timeout()
+		if !l.backgroundSince.IsZero() && timeout != lockTimeoutNever && time.Since(l.backgroundSince) >= timeout {
+			l.lock()
+		}
+		l.backgroundSince = time.Time{}
+	})
+
+	// keys typed outside of the entries, which handle their own keys
+	i.Window.Canvas().SetOnTypedKey(func(*fyne.KeyEvent) { l.touch() })
+	i.Window.Canvas().SetOnTypedRune(func(rune) { l.touch() })
+
+	l.lock()
+	l.touch()
+	go func() {
+		ticker := time.NewTicker(instrumentInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-i.ctx.Done():
+				return
+			case <-ticker.C:
+				fyne.Do(l.instrument)
+			}
+		}
+	}()
+	return container.NewStack(newActivityDetector(l.touch), content, l.screen)
+}
+
+// instrument wraps the callbacks of the widgets of the main window, the child windows and their dialogs
+// to record the activity, as the widgets handle the taps, scrolls and keys before the activity detector.
+func (l *appLock) instrument() {
+	if !l.enabled() || l.screen.Visible() || l.timeout() <= 0 {
+		return
+	}
+	seen := make(map[fyne.CanvasObject]bool, len(l.instrumented))
+	windows := append([]fyne.Window{l.Window}, l.childWindows...)
+	for _, w := range windows {
+		l.instrumentObject(w.Canvas().Content(), seen)
+		for _, o := range w.Canvas().Overlays().List() {
+			l.instrumentObject(o, seen)
+		}
+	}
+	l.instrumented = seen
+}
+
+func (l *appLock) instrumentObject(obj fyne.CanvasObject, seen map[fyne.CanvasObject]bool) {
+	if obj == nil || seen[obj] {
+		return
+	}
+	seen[obj] = true
+	wrapped := l.instrumented[obj]
+	switch o := obj.(type) {
+	case *fyne.Container:
+		for _, child := range o.Objects {
+			l.instrumentObject(child, seen)
+		}
+	case *container.Scroll:
+		if !wrapped {
+			o.OnScrolled = withActivity(l.touch, o.OnScrolled)
+		}
+		l.instrumentObject(o.Content, seen)
+	case *container.AppTabs:
+		if !wrapped {
+			o.OnSelected = withActivity(l.touch, o.OnSelected)
+		}
+		for _, item := range o.Items {
+			l.instrumentObject(item.Content, seen)
+		}
+	case *widget.PopUp:
+		l.instrumentObject(o.Content, seen)
+	case *widget.Card:
+		l.instrumentObject(o.Content, seen)
+	case *widget.Accordion:
+		for _, item := range o.Items {
+			l.instrumentObject(item.Detail, seen)
+		}
+	case *widget.Form:
+		// a form shows its buttons only for the callbacks that are set
+		if !wrapped && o.OnSubmit != nil {
+			o.OnSubmit = l.withActivity(o.OnSubmit)
+		}
+		for _, item := range o.Items {
+			l.instrumentObject(item.Widget, seen)
+		}
+	case *widget.Button:
+		if !wrapped && o.OnTapped != nil {
+			o.OnTapped = l.withActivity(o.OnTapped)
+		}
+	case *widget.Hyperlink:
+		// a hyperlink opens its URL without a callback
+		if !wrapped && o.OnTapped != nil {
+			o.OnTapped = l.withActivity(o.OnTapped)
+		}
+	case *widget.Entry:
+		if !wrapped {
+			l.instrumentEntry(o)
+		}
+	case *widget.SelectEntry:
+		if !wrapped {
+			l.instrumentEntry(&o.Entry)
+		}
+	case *widget.Check:
+		if !wrapped {
+			o.OnChanged = withActivity(l.touch, o.OnChanged)
+		}
+	case *widget.Select:
+		if !wrapped {
+			o.OnChanged = withActivity(l.touch, o.OnChanged)
+		}
+	case *widget.RadioGroup:
+		if !wrapped {
+			o.OnChanged = withActivity(l.touch, o.OnChanged)
+		}
+	case *widget.Slider:
+		if !wrapped {
+			o.OnChanged = withActivity(l.touch, o.OnChanged)
+		}
+	case *widget.List:
+		// a list selects its items only with a callback
+		if !wrapped && o.OnSelected != nil {
+			o.OnSelected = withActivity(l.touch, o.OnSelected)
+		}
+	}
+}
+
+func (l *appLock) instrumentEntry(e *widget.Entry) {
+	e.OnChanged = withActivity(l.touch, e.OnChanged)
+	e.OnCursorChanged = l.withActivity(e.OnCursorChanged)
+}
+
+// withActivity returns a callback recording the activity before calling f, if not nil.
+func (l *appLock) withActivity(f func()) func() {
+	return func() {
+		l.touch()
+		if f != nil {
+			f()
+		}
+	}
+}
+
+// withActivity returns a callback with an argument recording the activity before calling f, if not nil.
+func withActivity[T any](touch func(), f func(T)) func(T) {
+	return func(v T) {
+		touch()
+		if f != nil {
+			f(v)
+		}
+	}
+}
+
+// touch records an activity of the user and starts the idle timer if it is not running.
+func (l *appLock) touch() {
+	l.lastActivity = time.Now()
+	if l.idleTimer == nil {
+		l.checkIdle()
+	}
+}
+
+// restartIdleTimer applies a new timeout or the end of the lock to the idle timer.
+func (l *appLock) restartIdleTimer() {
+	if l.idleTimer != nil {
+		l.idleTimer.Stop()
+		l.idleTimer = nil
+	}
+	l.touch()
+}
+
+// checkIdle locks the app if there was no activity for the timeout, or checks again once there could
+// have been none for the timeout. The timer is not restarted on every activity, which is frequent.
+func (l *appLock) checkIdle() {
+	l.idleTimer = nil
+	timeout := l.timeout()
+	// locking immediately only applies to the background
+	if !l.enabled() || l.screen.Visible() || timeout <= 0 {
+		return
+	}
+	idle := time.Since(l.lastActivity)
+	if idle >= timeout {
+		l.lock()
+		return
+	}
+	l.idleTimer = time.AfterFunc(timeout-idle, func() {
+		fyne.Do(l.checkIdle)
+	})
+}
+
+func (l *appLock) enabled() bool {
+	return l.pin != nil && l.pin.Enabled()
+}
+
+func (l *appLock) timeout() time.Duration {
+	if l.nodeConfig.isKeyStoreMem {
+		return defaultLockTimeout
+	}
+	seconds := l.app.Preferences().IntWithFallback(lockTimeoutPrefKey, int(defaultLockTimeout/time.Second))
+	if seconds < 0 {
+		return lockTimeoutNever
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// lock covers the app with the lock screen and closes the open dialogs and child windows, which may
+// show secrets.
+func (l *appLock) lock() {
+	if !l.enabled() {
+		return
+	}
+	l.closeChildWindows()
+	overlays := l.Window.Canvas().Overlays()
+	for _, o := range overlays.List() {
+		overlays.Remove(o)
+	}
+	l.content.Hide()
+	l.pinEntry.SetText("")
+	l.screen.Show()
+	l.Window.Canvas().Focus(l.pinEntry)
+}
+
+func (l *appLock) unlock() {
+	pin := l.pinEntry.Text
+	go func() {
+		err := l.pin.Verify(pin)
+		fyne.Do(func() {
+			l.pinEntry.SetText("")
+			if err != nil {
+				l.showError(err)
+				return
+			}
+			l.screen.Hide()
+			l.content.Show()
+			l.restartIdleTimer()
+		})
+	}()
+}
+
+// authenticate asks for the PIN, or the node password if no PIN is set, before running onSuccess.
+func (i *index) authenticate(w fyne.Window, title string, onSuccess func()) {
+	entry := widget.NewPasswordEntry()
+	text := "Node password"
+	if i.appLock.enabled() {
+		text = "PIN"
+	}
+	items := []*widget.FormItem{{Text: text, Widget: entry}}
+	d := dialog.NewForm(title, "Confirm", "Cancel", items, func(ok bool) {
+		if !ok {
+			return
+		}
+		secret := entry.Text
+		go func() {
+			var err error
+			if i.appLock.enabled() {
+				err = i.appLock.pin.Verify(secret)
+			} else if subtle.ConstantTimeCompare([]byte(secret), []byte(i.nodeConfig.password)) != 1 {
+				err = fmt.Errorf("wrong password")
+			}
+			if err != nil {
+				i.showErrorIn(w, err)
+				return
+			}
+			fyne.Do(onSuccess)
+		}()
+	}, w)
+	parentSize := w.Canvas().Size()
+	d.Resize(fyne.NewSize(parentSize.Width*90/100, 0))
+	d.Show()
+}
+
+// appLockButton opens the app lock settings: set, change or remove the PIN, the idle timeout and lock now.
+func (i *index) appLockButton() *widget.Button {
+	button := widget.NewButtonWithIcon("App lock", theme.VisibilityOffIcon(), func() {
+		i.showAppLockSettings()
+	})
+	if i.appLock.pin == nil {
+		button.Hide()
+	}
+	return button
+}
+
+func (i *index) showAppLockSettings() {
+	l := i.appLock
+	var d dialog.Dialog
+	labels := make([]string, 0, len(lockTimeouts))
+	selected := ""
+	for _, t := range lockTimeouts {
+		labels = append(labels, t.label)
+		if t.timeout == l.timeout() {
+			selected = t.label
+		}
+	}
+	timeoutSelect := widget.NewSelect(labels, func(s string) {
+		for _, t := range lockTimeouts {
+			if t.label != s {
+				continue
+			}
+			seconds := int(t.timeout / time.Second)
+			if t.timeout == lockTimeoutNever {
+				seconds = lockTimeoutNever
+			}
+			i.setPreference(lockTimeoutPrefKey, seconds)
+			l.restartIdleTimer()
+		}
+	})
+	timeoutSelect.SetSelected(selected)
+
+	content := container.NewVBox()
+	if l.enabled() {
+		changeButton := widget.NewButton("Change PIN", func() {
+			i.authenticate(i.Window, "Change PIN", func() {
+				d.Hide()
+				i.showSetPIN()
+			})
+		})
+		removeButton := widget.NewButton("Remove PIN", func() {
+			i.authenticate(i.Window, "Remove PIN", func() {
+				d.Hide()
+				if err := l.pin.Remove(); err != nil {
+					i.showError(err)
+				}
+			})
+		})
+		removeButton.Importance = widget.DangerImportance
+		lockButton := widget.NewButton("Lock now", func() {
+			d.Hide()
+			l.lock()
+		})
+		lockButton.Importance = widget.HighImportance
+		content.Add(widget.NewForm(widget.NewFormItem("Lock when idle", timeoutSelect)))
+		content.Add(changeButton)
+		content.Add(removeButton)
+		content.Add(lockButton)
+	} else {
+		setButton := widget.NewButton("Set PIN", func() {
+			d.Hide()
+			i.showSetPIN()
+		})
+		setButton.Importance = widget.HighImportance
+		content.Add(widget.NewLabel("Lock the app with a PIN on launch,\nafter being idle and\nbefore sensitive actions."))
+		content.Add(setButton)
+	}
+
+	d = dialog.NewCustom("App lock", "Close", content, i.Window)
+	parentSize := i.Window.Canvas().Size()
+	d.Resize(fyne.NewSize(parentSize.Width*90/100, 0))
+	d.Show()
+}
+
+func (i *index) showSetPIN() {
+	pinEntry := widget.NewPasswordEntry()
+	confirmEntry := widget.NewPasswordEntry()
+	confirmEntry.Validator = func(s string) error {
+		if s != pinEntry.Text {
+			return fmt.Errorf("PINs do not match")
+		}
+		return nil
+	}
+	items := []*widget.FormItem{
+		{Text: "PIN", Widget: pinEntry, HintText: fmt.Sprintf("At least %d digits", applock.MinPINLength)},
+		{Text: "Confirm", Widget: confirmEntry},
+	}
+	d := dialog.NewForm("Set PIN", "Save", "Cancel", items, func(ok bool) {
+		if !ok {
+			return
+		}
+		pin := pinEntry.Text
+		go func() {
+			if err := i.appLock.pin.Set(pin); err != nil {
+				i.showError(fmt.Errorf("failed to save the PIN: %w", err))
+				return
+			}
+			i.logger.Log("App lock PIN set")
+		}()
+	}, i.Window)
+	parentSize := i.Window.Canvas().Size()
+	d.Resize(fyne.NewSize(parentSize.Width*90/100, 0))
+	d.Show()
+}
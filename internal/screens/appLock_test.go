@@ -0,0 +1,146 @@
+package screens
+
+import (
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/Solar-Punk-Ltd/swarm-mobile/internal/applock"
+	"github.com/Solar-Punk-Ltd/swarm-mobile/internal/node/nodetest"
+)
+
+// newTestAppLock returns an unlocked app lock with the PIN 1234 and the given idle timeout.
+func newTestAppLock(t *testing.T, timeout time.Duration) (*appLock, *activityDetector) {
+	t.Helper()
+	i := newTestIndex(t, nodetest.New())
+	pin, err := applock.Open(i.rootPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pin.Set("1234"); err != nil {
+		t.Fatal(err)
+	}
+	i.setPreference(lockTimeoutPrefKey, int(timeout/time.Second))
+
+	wrapped := i.newAppLock(widget.NewLabel("secret")).(*fyne.Container)
+	i.Window.SetContent(wrapped)
+	l := i.appLock
+	t.Cleanup(func() {
+		if l.idleTimer != nil {
+			l.idleTimer.Stop()
+		}
+	})
+	if !l.screen.Visible() {
+		t.Fatal("the app is not locked on launch")
+	}
+	l.screen.Hide()
+	l.content.Show()
+	l.restartIdleTimer()
+	return l, wrapped.Objects[0].(*activityDetector)
+}
+
+func TestAppLockIdle(t *testing.T) {
+	l, _ := newTestAppLock(t, time.Minute)
+	if l.idleTimer == nil {
+		t.Fatal("the idle timer is not running")
+	}
+
+	l.checkIdle()
+	if l.screen.Visible() {
+		t.Fatal("the app is locked while in use")
+	}
+
+	l.lastActivity = time.Now().Add(-2 * time.Minute)
+	l.checkIdle()
+	if !l.screen.Visible() || l.content.Visible() {
+		t.Fatal("the app is not locked after being idle")
+	}
+	if l.idleTimer != nil {
+		t.Error("the idle timer runs while locked")
+	}
+}
+
+func TestAppLockActivity(t *testing.T) {
+	l, detector := newTestAppLock(t, time.Minute)
+
+	l.lastActivity = time.Now().Add(-2 * time.Minute)
+	test.Tap(detector)
+	l.checkIdle()
+	if l.screen.Visible() {
+		t.Fatal("a tap does not reset the idle time")
+	}
+
+	l.lastActivity = time.Now().Add(-2 * time.Minute)
+	l.Window.Canvas().OnTypedKey()(&fyne.KeyEvent{Name: fyne.KeyA})
+	l.checkIdle()
+	if l.screen.Visible() {
+		t.Fatal("a typed key does not reset the idle time")
+	}
+}
+
+func TestAppLockWidgetActivity(t *testing.T) {
+	l, _ := newTestAppLock(t, time.Minute)
+	tapped := 0
+	button := widget.NewButton("button", func() { tapped++ })
+	entry := widget.NewEntry()
+	child, _ := l.newChildWindow("child")
+	child.SetContent(container.NewVBox(button, entry))
+	l.instrument()
+	l.instrument()
+
+	l.lastActivity = time.Now().Add(-2 * time.Minute)
+	test.Tap(button)
+	l.checkIdle()
+	if l.screen.Visible() {
+		t.Fatal("a button tap does not reset the idle time")
+	}
+	if tapped != 1 {
+		t.Errorf("the button callback ran %d times, want 1", tapped)
+	}
+
+	l.lastActivity = time.Now().Add(-2 * time.Minute)
+	entry.SetText("typed")
+	l.checkIdle()
+	if l.screen.Visible() {
+		t.Fatal("typing in an entry does not reset the idle time")
+	}
+}
+
+func TestAppLockClosesChildWindows(t *testing.T) {
+	l, _ := newTestAppLock(t, time.Minute)
+	child, ctx := l.newChildWindow("child")
+	child.Show()
+
+	l.lock()
+	if len(l.childWindows) != 0 || ctx.Err() == nil {
+		t.Error("the child window is open after locking")
+	}
+}
+
+func TestAppLockIdleDisabled(t *testing.T) {
+	for _, timeout := range []time.Duration{0, lockTimeoutNever * time.Second} {
+		l, _ := newTestAppLock(t, timeout)
+		l.lastActivity = time.Now().Add(-time.Hour)
+		l.checkIdle()
+		if l.screen.Visible() {
+			t.Errorf("timeout %s locks the app in the foreground", timeout)
+		}
+	}
+}
+
+func TestActivityDetectorBehindContent(t *testing.T) {
+	activity, tapped := 0, false
+	button := widget.NewButton("button", func() { tapped = true })
+	c := container.NewStack(newActivityDetector(func() { activity++ }), button)
+	w := test.NewTempWindow(t, c)
+	w.Resize(fyne.NewSize(200, 200))
+
+	test.TapCanvas(w.Canvas(), fyne.NewPos(100, 100))
+	if !tapped || activity != 0 {
+		t.Errorf("the detector takes the taps of the content")
+	}
+}
@@ -0,0 +1,348 @@
+package screens
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/Solar-Punk-Ltd/swarm-mobile/internal/node"
+)
+
+const (
+	plurPerBZZ             = 1e16
+	bzzDecimals            = 16
+	batchesRefreshInterval = 30 * time.Second
+	// a batch is reported as almost full or expiring past these limits
+	batchFullWarning   = 90
+	batchExpiryWarning = 7 * 24 * time.Hour
+	maxDiluteSteps     = 4
+)
+
+// batchWarnings returns the problems of a batch that need the attention of the user.
+func batchWarnings(b node.Batch) []string {
+	warnings := []string{}
+	if !b.Usable {
+		warnings = append(warnings, "not usable yet")
+	}
+	if b.UtilizationPercent() >= batchFullWarning {
+		warnings = append(warnings, "almost full")
+	}
+	if b.TTL < batchExpiryWarning {
+		warnings = append(warnings, "expiring soon")
+	}
+	return warnings
+}
+
+func batchName(b node.Batch) string {
+	if b.Label == "" {
+		return shortenHashOrAddress(b.ID)
+	}
+	return fmt.Sprintf("%s (%s)", b.Label, shortenHashOrAddress(b.ID))
+}
+
+// batchesBrowser is the postage batch dashboard, refreshed periodically while it is open.
+type batchesBrowser struct {
+	*index
+	window     fyne.Window
+	ctx        context.Context
+	mu         sync.Mutex
+	batches    []node.Batch
+	list       *widget.List
+	priceLabel *widget.Label
+	emptyLabel *widget.Label
+}
+
+func (i *index) listBatchesButton(minSize fyne.Size) *widget.Button {
+	return widget.NewButton("Postage batches", func() {
+		child, ctx := i.newChildWindow("Postage batches")
+		b := &batchesBrowser{index: i, window: child, ctx: ctx}
+		child.SetContent(b.content())
+		go b.refreshLoop()
+
+		size := child.Canvas().Content().MinSize()
+		if size.Width < minSize.Width {
+			size.Width = minSize.Width
+		}
+		if size.Height < minSize.Height {
+			size.Height = minSize.Height
+		}
+		child.Resize(size)
+		child.Show()
+	})
+}
+
+func (b *batchesBrowser) content() fyne.CanvasObject {
+	b.list = widget.NewList(
+		func() int {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			return len(b.batches)
+		},
+		func() fyne.CanvasObject {
+			name := widget.NewLabel("")
+			name.Truncation = fyne.TextTruncateEllipsis
+			name.TextStyle.Bold = true
+			usage := widget.NewProgressBar()
+			warning := widget.NewLabel("")
+			warning.Importance = widget.WarningImportance
+			return container.NewVBox(name, usage, widget.NewLabel(""), warning)
+		},
+		func(id widget.ListItemID, o fyne.CanvasObject) {
+			batch := b.batch(id)
+			objects := o.(*fyne.Container).Objects
+			objects[0].(*widget.Label).SetText(batchName(batch))
+			objects[1].(*widget.ProgressBar).SetValue(batch.UtilizationPercent() / 100)
+			objects[2].(*widget.Label).SetText(fmt.Sprintf("depth %d, TTL %s", batch.Depth, formatTTL(batch.TTL)))
+			warning := objects[3].(*widget.Label)
+			if warnings := batchWarnings(batch); len(warnings) != 0 {
+				warning.SetText(strings.Join(warnings, ", "))
+				warning.Show()
+			} else {
+				warning.Hide()
+			}
+		},
+	)
+	b.list.OnSelected = func(id widget.ListItemID) {
+		b.list.Unselect(id)
+		b.showDetails(b.batch(id))
+	}
+
+	b.priceLabel = widget.NewLabel("")
+	b.emptyLabel = widget.NewLabel("No postage batches")
+	b.emptyLabel.Hide()
+	refreshButton := widget.NewButtonWithIcon("Refresh", theme.ViewRefreshIcon(), func() {
+		go b.reload()
+	})
+
+	return container.NewBorder(b.priceLabel, container.NewVBox(b.emptyLabel, refreshButton), nil, nil, b.list)
+}
+
+func (b *batchesBrowser) batch(id widget.ListItemID) node.Batch {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.batches[id]
+}
+
+func (b *batchesBrowser) refreshLoop() {
+	b.reload()
+	ticker := time.NewTicker(batchesRefreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			b.reload()
+		case <-b.ctx.Done():
+			return
+		}
+	}
+}
+
+func (b *batchesBrowser) reload() {
+	batches, err := b.node.Batches(b.ctx)
+	if err != nil {
+		if b.ctx.Err() == nil {
+			b.logger.Log(fmt.Sprintf("failed to list postage batches: %s", err.Error()))
+		}
+		return
+	}
+	price := "Current price: unknown"
+	state, err := b.node.ChainState(b.ctx)
+	if err == nil {
+		price = fmt.Sprintf("Current price: %s PLUR per chunk per block", state.CurrentPrice.String())
+	}
+
+	b.mu.Lock()
+	b.batches = batches
+	b.mu.Unlock()
+	fyne.Do(func() {
+		b.priceLabel.SetText(price)
+		if len(batches) == 0 {
+			b.emptyLabel.Show()
+		} else {
+			b.emptyLabel.Hide()
+		}
+		b.list.Refresh()
+	})
+}
+
+func (b *batchesBrowser) showDetails(batch node.Batch) {
+	label := batch.Label
+	if label == "" {
+		label = "none"
+	}
+	value := node.BatchCost(batch.Amount, batch.Depth)
+	details := widget.NewForm(
+		widget.NewFormItem("Batch ID", widget.NewLabel(shortenHashOrAddress(batch.ID))),
+		widget.NewFormItem("Label", widget.NewLabel(label)),
+		widget.NewFormItem("Depth", widget.NewLabel(fmt.Sprintf("%d", batch.Depth))),
+		widget.NewFormItem("Bucket depth", widget.NewLabel(fmt.Sprintf("%d", batch.BucketDepth))),
+		widget.NewFormItem("Utilization", widget.NewLabel(fmt.Sprintf("%.1f%% (%d of %d chunks in the fullest bucket)", batch.UtilizationPercent(), batch.Utilization, batch.BucketCapacity()))),
+		widget.NewFormItem("Amount", widget.NewLabel(fmt.Sprintf("%s PLUR per chunk, %s in total", batch.Amount.String(), formatBZZ(value)))),
+		widget.NewFormItem("TTL", widget.NewLabel(formatTTL(batch.TTL))),
+		widget.NewFormItem("Immutable", widget.NewLabel(fmt.Sprintf("%t", batch.Immutable))),
+	)
+	if warnings := batchWarnings(batch); len(warnings) != 0 {
+		warning := widget.NewLabel(strings.Join(warnings, ", "))
+		warning.Importance = widget.WarningImportance
+		details.Append("Warning", warning)
+	}
+
+	var d dialog.Dialog
+	copyButton := widget.NewButtonWithIcon("Copy batch ID", theme.ContentCopyIcon(), func() {
+		b.window.Clipboard().SetContent(batch.ID)
+	})
+	topUpButton := widget.NewButtonWithIcon("Top up", theme.ContentAddIcon(), func() {
+		d.Hide()
+		go b.showTopUp(batch)
+	})
+	diluteButton := widget.NewButtonWithIcon("Dilute", theme.ZoomInIcon(), func() {
+		d.Hide()
+		b.showDilute(batch)
+	})
+	if !batch.Usable {
+		topUpButton.Disable()
+		diluteButton.Disable()
+	}
+
+	d = dialog.NewCustom(batchName(batch), "Close", container.NewVBox(details, copyButton, topUpButton, diluteButton), b.window)
+	parentSize := b.window.Canvas().Size()
+	d.Resize(fyne.NewSize(parentSize.Width*90/100, 0))
+	d.Show()
+}
+
+// showTopUp asks for the amount per chunk to add to the batch, previewing its cost and the TTL it buys.
+func (b *batchesBrowser) showTopUp(batch node.Batch) {
+	state, err := b.node.ChainState(b.ctx)
+	if err != nil {
+		b.showErrorIn(b.window, fmt.Errorf("failed to get the current price: %w", err))
+		return
+	}
+	fyne.Do(func() {
+		b.topUpForm(batch, state)
+	})
+}
+
+func (b *batchesBrowser) topUpForm(batch node.Batch, state node.ChainState) {
+	preview := widget.NewLabel("")
+	preview.Wrapping = fyne.TextWrapWord
+	amountEntry := widget.NewEntry()
+	amountEntry.SetText(defaultAmount)
+	amountEntry.Validator = func(s string) error {
+		if _, ok := parseAmount(s); !ok {
+			return fmt.Errorf("invalid amount")
+		}
+		return nil
+	}
+	amountEntry.OnChanged = func(s string) {
+		amount, ok := parseAmount(s)
+		if !ok {
+			preview.SetText("")
+			return
+		}
+		ttl := state.TTL(amount, b.nodeConfig.network.BlockTime)
+		preview.SetText(fmt.Sprintf("Cost: %s\nExtends the TTL by %s to %s", formatBZZ(node.BatchCost(amount, batch.Depth)), formatTTL(ttl), formatTTL(batch.TTL+ttl)))
+	}
+	amountEntry.OnChanged(amountEntry.Text)
+
+	items := []*widget.FormItem{
+		{Text: "Amount", Widget: amountEntry, HintText: "PLUR per chunk"},
+		{Text: "", Widget: preview},
+	}
+	d := dialog.NewForm(fmt.Sprintf("Top up %s", batchName(batch)), "Top up", "Cancel", items, func(ok bool) {
+		if !ok {
+			return
+		}
+		amount, _ := parseAmount(amountEntry.Text)
+		go b.runBatchTransaction(fmt.Sprintf("Topping up %s with %s", batchName(batch), formatBZZ(node.BatchCost(amount, batch.Depth))), func() (common.Hash, error) {
+			return b.node.TopUpBatch(b.ctx, batch.ID, amount)
+		})
+	}, b.window)
+	parentSize := b.window.Canvas().Size()
+	d.Resize(fyne.NewSize(parentSize.Width*90/100, 0))
+	d.Show()
+}
+
+// showDilute asks for the new depth of the batch, previewing its capacity and the TTL left after the dilution.
+func (b *batchesBrowser) showDilute(batch node.Batch) {
+	depths := []string{}
+	for depth := batch.Depth + 1; depth <= batch.Depth+maxDiluteSteps; depth++ {
+		depths = append(depths, strconv.Itoa(int(depth)))
+	}
+
+	preview := widget.NewLabel("")
+	preview.Wrapping = fyne.TextWrapWord
+	depth := batch.Depth + 1
+	depthSelect := widget.NewSelect(depths, func(s string) {
+		v, err := strconv.ParseUint(s, 10, 8)
+		if err != nil {
+			return
+		}
+		depth = uint8(v)
+		factor := time.Duration(1) << (depth - batch.Depth)
+		preview.SetText(fmt.Sprintf("Cost: 0 %s, only gas is paid\nCapacity grows %d times, the TTL drops to %s", SwarmTokenSymbol, factor, formatTTL(batch.TTL/factor)))
+	})
+	depthSelect.SetSelectedIndex(0)
+
+	items := []*widget.FormItem{
+		{Text: "New depth", Widget: depthSelect},
+		{Text: "", Widget: preview},
+	}
+	d := dialog.NewForm(fmt.Sprintf("Dilute %s", batchName(batch)), "Dilute", "Cancel", items, func(ok bool) {
+		if !ok {
+			return
+		}
+		go b.runBatchTransaction(fmt.Sprintf("Diluting %s to depth %d", batchName(batch), depth), func() (common.Hash, error) {
+			return b.node.DiluteBatch(b.ctx, batch.ID, depth)
+		})
+	}, b.window)
+	parentSize := b.window.Canvas().Size()
+	d.Resize(fyne.NewSize(parentSize.Width*90/100, 0))
+	d.Show()
+}
+
+// runBatchTransaction shows a progress dialog until the transaction is mined, then reloads the batches.
+func (b *batchesBrowser) runBatchTransaction(message string, send func() (common.Hash, error)) {
+	var progress dialog.Dialog
+	fyne.Do(func() {
+		progress = dialog.NewCustomWithoutButtons(message, container.NewVBox(
+			widget.NewLabel("Waiting for the transaction to be mined"),
+			widget.NewProgressBarInfinite(),
+		), b.window)
+		progress.Show()
+	})
+	hash, err := send()
+	fyne.Do(func() {
+		progress.Hide()
+	})
+	if err != nil {
+		b.showErrorIn(b.window, err)
+		return
+	}
+
+	b.logger.Log(fmt.Sprintf("%s: transaction %s", message, hash.String()))
+	fyne.Do(func() {
+		d := dialog.NewCustom("Transaction mined", "Ok", b.copyDialog(shortenHashOrAddress(hash.String()), hash.String()), b.window)
+		d.Show()
+	})
+	b.reload()
+}
+
+func parseAmount(s string) (*big.Int, bool) {
+	amount, ok := new(big.Int).SetString(strings.TrimSpace(s), 10)
+	if !ok || amount.Sign() <= 0 {
+		return nil, false
+	}
+	return amount, true
+}
@@ -0,0 +1,151 @@
+package screens
+
+import (
+	"fmt"
+	"math/big"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/Solar-Punk-Ltd/swarm-mobile/internal/node"
+)
+
+// chequebookContent holds the deposit and withdraw actions. onDone is called once a transaction is mined.
+func (i *index) chequebookContent(onDone func()) fyne.CanvasObject {
+	depositButton := widget.NewButtonWithIcon("Deposit", theme.ContentAddIcon(), func() {
+		i.showChequebookAmount(true, onDone)
+	})
+	withdrawButton := widget.NewButtonWithIcon("Withdraw", theme.ContentRemoveIcon(), func() {
+		i.authenticate(i.Window, "Withdraw", func() {
+			i.showChequebookAmount(false, onDone)
+		})
+	})
+	return container.NewGridWithColumns(2, depositButton, withdrawButton)
+}
+
+func chequebookAction(deposit bool) string {
+	if deposit {
+		return "Deposit"
+	}
+	return "Withdraw"
+}
+
+// showChequebookAmount asks for the amount of xBZZ to move into or out of the chequebook.
+func (i *index) showChequebookAmount(deposit bool, onDone func()) {
+	amountEntry := widget.NewEntry()
+	amountEntry.SetPlaceHolder("1.5")
+	amountEntry.Validator = func(s string) error {
+		_, err := parseBZZ(s)
+		return err
+	}
+	hint := "from the wallet into the chequebook"
+	if !deposit {
+		hint = "from the chequebook into the wallet"
+	}
+	items := []*widget.FormItem{
+		{Text: "Amount", Widget: amountEntry, HintText: fmt.Sprintf("%s %s", SwarmTokenSymbol, hint)},
+	}
+
+	d := dialog.NewForm(fmt.Sprintf("%s %s", chequebookAction(deposit), SwarmTokenSymbol), "Next", "Cancel", items, func(ok bool) {
+		if !ok {
+			return
+		}
+		amount, err := parseBZZ(amountEntry.Text)
+		if err != nil {
+			i.showError(err)
+			return
+		}
+		go i.confirmChequebookTransaction(deposit, amount, onDone)
+	}, i.Window)
+	parentSize := i.Window.Canvas().Size()
+	d.Resize(fyne.NewSize(parentSize.Width*90/100, 0))
+	d.Show()
+}
+
+// confirmChequebookTransaction shows the amount and the estimated gas fee before sending the transaction.
+func (i *index) confirmChequebookTransaction(deposit bool, amount *big.Int, onDone func()) {
+	i.showProgressWithMessage("Estimating the gas fee")
+	fee := "unknown"
+	wallet, err := i.node.Wallet(i.ctx)
+	if err == nil {
+		var estimate *big.Int
+		estimate, err = node.EstimateChequebookFee(i.ctx, i.nodeConfig.rpcEndpoint, wallet.Address, wallet.Chequebook, amount, deposit)
+		if err == nil {
+			fee = formatNativeToken(estimate)
+		}
+	}
+	i.hideProgress()
+	if err != nil {
+		i.logger.Log(fmt.Sprintf("failed to estimate the gas fee: %s", err.Error()))
+	}
+
+	message := fmt.Sprintf("%s %s?\nEstimated gas fee: %s", chequebookAction(deposit), formatBZZ(amount), fee)
+	fyne.Do(func() {
+		dialog.ShowConfirm(fmt.Sprintf("Confirm %s", chequebookAction(deposit)), message, func(ok bool) {
+			if !ok {
+				return
+			}
+			go i.sendChequebookTransaction(deposit, amount, onDone)
+		}, i.Window)
+	})
+}
+
+// sendChequebookTransaction sends the transaction and shows its hash and status until it is mined.
+func (i *index) sendChequebookTransaction(deposit bool, amount *big.Int, onDone func()) {
+	var (
+		hash common.Hash
+		err  error
+	)
+	i.showProgressWithMessage(fmt.Sprintf("Sending the %s transaction", chequebookAction(deposit)))
+	if deposit {
+		hash, err = i.node.ChequebookDeposit(i.ctx, amount)
+	} else {
+		hash, err = i.node.ChequebookWithdraw(i.ctx, amount)
+	}
+	i.hideProgress()
+	if err != nil {
+		i.showError(err)
+		return
+	}
+	i.logger.Log(fmt.Sprintf("chequebook %s of %s: transaction %s", chequebookAction(deposit), formatBZZ(amount), hash.String()))
+
+	i.waitForTransaction(i.Window, fmt.Sprintf("%s transaction", chequebookAction(deposit)), hash)
+	onDone()
+}
+
+// waitForTransaction shows the hash of a sent transaction in w with its status, pending until it is mined.
+func (i *index) waitForTransaction(w fyne.Window, title string, hash common.Hash) {
+	status := widget.NewLabel("Pending")
+	fyne.Do(func() {
+		content := container.NewVBox(i.copyDialog(shortenHashOrAddress(hash.String()), hash.String()), status)
+		d := dialog.NewCustom(title, "Close", content, w)
+		parentSize := w.Canvas().Size()
+		d.Resize(fyne.NewSize(parentSize.Width*90/100, 0))
+		d.Show()
+	})
+
+	receipt, err := i.node.WaitForReceipt(i.ctx, hash)
+	switch {
+	case err != nil && i.ctx.Err() != nil:
+		return
+	case err != nil:
+		i.logger.Log(fmt.Sprintf("failed to wait for transaction %s: %s", hash.String(), err.Error()))
+		fyne.Do(func() {
+			status.SetText(fmt.Sprintf("Unknown: %s", err.Error()))
+		})
+	case receipt.Status != types.ReceiptStatusSuccessful:
+		fyne.Do(func() {
+			status.SetText(fmt.Sprintf("Failed in block %s", receipt.BlockNumber.String()))
+		})
+	default:
+		fyne.Do(func() {
+			status.SetText(fmt.Sprintf("Confirmed in block %s", receipt.BlockNumber.String()))
+		})
+	}
+}
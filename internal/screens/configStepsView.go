@@ -3,14 +3,19 @@ package screens
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/data/binding"
 	"fyne.io/fyne/v2/widget"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethersphere/bee/v2/pkg/api"
+
+	"github.com/Solar-Punk-Ltd/swarm-mobile/internal/node"
 )
 
 type nodeConfig struct {
@@ -20,6 +25,7 @@ type nodeConfig struct {
 	swapEnable     bool
 	natAddress     string
 	rpcEndpoint    string
+	network        node.Network
 	isKeyStoreMem  bool
 }
 
@@ -39,7 +45,14 @@ func (i *index) showPasswordView() fyne.CanvasObject {
 		content.Refresh()
 	})
 	nextButton.Importance = widget.HighImportance
-	content.Objects = []fyne.CanvasObject{container.NewBorder(passwordEntry, nextButton, nil, nil)}
+	onImported := func(password string) {
+		i.nodeConfig.password = password
+		content.Objects = []fyne.CanvasObject{i.showWelcomeMessageView()}
+		content.Refresh()
+	}
+	importButton := i.importKeysButton(onImported)
+	importWalletButton := i.importWalletButton(passwordEntry, onImported)
+	content.Objects = []fyne.CanvasObject{container.NewBorder(passwordEntry, container.NewVBox(nextButton, importButton, importWalletButton, i.profilesButton()), nil, nil)}
 	i.content = content
 	i.view = container.NewBorder(container.NewVBox(i.intro), nil, nil, nil, content)
 	i.view.Refresh()
@@ -69,7 +82,7 @@ func (i *index) showWelcomeMessageView() fyne.CanvasObject {
 		}
 
 		i.nodeConfig.welcomeMessage = welcomeMessageEntry.Text
-		content.Objects = []fyne.CanvasObject{i.showNodeModeSelectionView()}
+		content.Objects = []fyne.CanvasObject{i.showNetworkView()}
 		content.Refresh()
 	})
 
@@ -87,7 +100,100 @@ func (i *index) showWelcomeMessageView() fyne.CanvasObject {
 	return content
 }
 
-func (i *index) getNodeModeRadio() *widget.RadioGroup {
+func (i *index) loadNetwork() node.Network {
+	switch i.getPreferenceString(networkPrefKey) {
+	case node.TestnetNetworkName:
+		return node.Testnet
+	case node.CustomNetworkName:
+		networkID, err := strconv.ParseUint(i.getPreferenceString(networkIDPrefKey), 10, 64)
+		if err == nil {
+			var network node.Network
+			network, err = node.CustomNetwork(networkID, i.getPreferenceStringList(bootnodesPrefKey))
+			if err == nil {
+				return network
+			}
+		}
+		i.logger.Log(fmt.Sprintf("invalid custom network in preferences, using mainnet: %s", err.Error()))
+	}
+	return node.Mainnet
+}
+
+func (i *index) saveNetwork(network node.Network) {
+	i.setPreference(networkPrefKey, network.Name)
+	i.setPreference(networkIDPrefKey, strconv.FormatUint(network.NetworkID, 10))
+	i.setPreference(bootnodesPrefKey, network.Bootnodes)
+}
+
+func (i *index) showNetworkView() fyne.CanvasObject {
+	i.intro.SetText("Choose the swarm network to join")
+	content := container.NewStack()
+	networkIDEntry := widget.NewEntry()
+	networkIDEntry.SetPlaceHolder("Network ID")
+	bootnodesEntry := widget.NewMultiLineEntry()
+	bootnodesEntry.SetPlaceHolder("Bootnode multiaddrs, one per line")
+	if i.nodeConfig.network.Name == node.CustomNetworkName {
+		networkIDEntry.SetText(strconv.FormatUint(i.nodeConfig.network.NetworkID, 10))
+		bootnodesEntry.SetText(strings.Join(i.nodeConfig.network.Bootnodes, "\n"))
+	}
+	customBox := container.NewVBox(networkIDEntry, bootnodesEntry)
+	customBox.Hide()
+
+	networkRadio := widget.NewRadioGroup(
+		[]string{node.MainnetNetworkName, node.TestnetNetworkName, node.CustomNetworkName},
+		func(name string) {
+			if name == node.CustomNetworkName {
+				customBox.Show()
+			} else {
+				customBox.Hide()
+			}
+		},
+	)
+	networkRadio.SetSelected(i.nodeConfig.network.Name)
+
+	nextButton := widget.NewButton("Next", func() {
+		switch networkRadio.Selected {
+		case node.MainnetNetworkName:
+			i.nodeConfig.network = node.Mainnet
+		case node.TestnetNetworkName:
+			i.nodeConfig.network = node.Testnet
+		case node.CustomNetworkName:
+			networkID, err := strconv.ParseUint(networkIDEntry.Text, 10, 64)
+			if err != nil {
+				i.showError(fmt.Errorf("invalid network ID: %s", err.Error()))
+				return
+			}
+			network, err := node.CustomNetwork(networkID, strings.Split(bootnodesEntry.Text, "\n"))
+			if err != nil {
+				i.showError(err)
+				return
+			}
+			i.nodeConfig.network = network
+		default:
+			i.showError(fmt.Errorf("please select the network"))
+			return
+		}
+
+		i.logger.Log(fmt.Sprintf("Network selected: %s, network ID: %d", i.nodeConfig.network.Name, i.nodeConfig.network.NetworkID))
+		content.Objects = []fyne.CanvasObject{i.showNodeModeSelectionView()}
+		content.Refresh()
+	})
+
+	backButton := widget.NewButton("Back", func() {
+		content.Objects = []fyne.CanvasObject{i.showWelcomeMessageView()}
+		content.Refresh()
+	})
+	backButton.Importance = widget.WarningImportance
+	nextButton.Importance = widget.HighImportance
+	content.Objects = []fyne.CanvasObject{container.NewBorder(container.NewVBox(networkRadio, customBox), container.NewVBox(nextButton, backButton), nil, nil)}
+	i.content = content
+	i.view = container.NewBorder(container.NewVBox(i.intro), nil, nil, nil, content)
+	i.view.Refresh()
+
+	return content
+}
+
+// getNodeModeRadio returns the node mode choice, onChanged is called after a change if not nil.
+func (i *index) getNodeModeRadio(onChanged func()) *widget.RadioGroup {
 	return widget.NewRadioGroup(
 		[]string{api.LightMode.String(), api.UltraLightMode.String()},
 		func(mode string) {
@@ -97,6 +203,9 @@ func (i *index) getNodeModeRadio() *widget.RadioGroup {
 				i.nodeConfig.swapEnable = false
 			}
 			i.logger.Log(fmt.Sprintf("Node mode selected: %s", mode))
+			if onChanged != nil {
+				onChanged()
+			}
 		},
 	)
 }
@@ -104,7 +213,7 @@ func (i *index) getNodeModeRadio() *widget.RadioGroup {
 func (i *index) showNodeModeSelectionView() fyne.CanvasObject {
 	i.intro.SetText("Choose the type of your node")
 	content := container.NewStack()
-	nodeModeRadio := i.getNodeModeRadio()
+	nodeModeRadio := i.getNodeModeRadio(nil)
 	nextButton := widget.NewButton("Next", func() {
 		if nodeModeRadio.Selected == "" {
 			i.showError(fmt.Errorf("please select the node mode"))
@@ -117,7 +226,7 @@ func (i *index) showNodeModeSelectionView() fyne.CanvasObject {
 	})
 
 	backButton := widget.NewButton("Back", func() {
-		content.Objects = []fyne.CanvasObject{i.showWelcomeMessageView()}
+		content.Objects = []fyne.CanvasObject{i.showNetworkView()}
 		content.Refresh()
 	})
 	backButton.Importance = widget.WarningImportance
@@ -179,19 +288,25 @@ func (i *index) showNATAddressView() fyne.CanvasObject {
 	return content
 }
 
-func (i *index) verifyRPCConnection(rpcEndpoint string) error {
+func (i *index) verifyRPCConnection(rpcEndpoint string, network node.Network) error {
 	i.logger.Log(fmt.Sprintf("verifying RPC endpoint connection: %s", rpcEndpoint))
 	// test endpoint is connectable
 	eth, err := ethclient.Dial(rpcEndpoint)
 	if err != nil {
 		return fmt.Errorf("rpc endpoint is invalid or not reachable: %w", err)
 	}
+	defer eth.Close()
 	// check connections
-	_, err = eth.ChainID(context.Background())
+	chainID, err := eth.ChainID(context.Background())
 	if err != nil {
 		return err
 	}
 
+	// the chain of a custom network is only known for the testnet network ID
+	if network.ChainID != 0 && chainID.Int64() != network.ChainID {
+		return fmt.Errorf("rpc endpoint is on chain %d but the %s network requires chain %d", chainID.Int64(), network.Name, network.ChainID)
+	}
+
 	return nil
 }
 
@@ -232,8 +347,25 @@ func (i *index) showStartView(firstStart bool) fyne.CanvasObject {
 	i.intro.TextStyle.Bold = true
 	content := container.NewStack()
 	overlayAddr := i.getPreferenceString(overlayAddrPrefKey)
+	ctx, cancel := context.WithCancel(i.ctx)
+	var startButton *widget.Button
+	var wallet *walletPanel
+	var walletContent fyne.CanvasObject
+	// light mode can only start once the wallet is funded
+	updateStartButton := func() {
+		if i.nodeConfig.swapEnable && wallet != nil && wallet.sufficient() != nil {
+			startButton.Disable()
+		} else {
+			startButton.Enable()
+		}
+	}
+	if overlayAddr != "" {
+		wallet, walletContent = i.newWalletPanel(ctx, common.HexToAddress(overlayAddr), func(bool) {
+			updateStartButton()
+		})
+	}
 
-	startButton := widget.NewButton("Start", func() {
+	startButton = widget.NewButton("Start", func() {
 		if i.nodeConfig.path == "" && !i.nodeConfig.isKeyStoreMem {
 			i.showError(fmt.Errorf("invalid app storage path"))
 			return
@@ -249,7 +381,7 @@ func (i *index) showStartView(firstStart bool) fyne.CanvasObject {
 				i.showError(fmt.Errorf("rpc endpoint is required in light mode"))
 				return
 			}
-			err := i.verifyRPCConnection(i.nodeConfig.rpcEndpoint)
+			err := i.verifyRPCConnection(i.nodeConfig.rpcEndpoint, i.nodeConfig.network)
 			if err != nil {
 				i.logger.Log(fmt.Sprintf("rpc endpoint error: %s", err.Error()))
 				i.showError(err)
@@ -259,6 +391,10 @@ func (i *index) showStartView(firstStart bool) fyne.CanvasObject {
 				i.showError(fmt.Errorf("Overlay address is not saved, need to start in ultra-light mode first"))
 				return
 			}
+			if err := wallet.sufficient(); err != nil {
+				i.showError(err)
+				return
+			}
 		} else {
 			if i.nodeConfig.rpcEndpoint != "" {
 				i.showError(fmt.Errorf("rpc endpoint must be empty in ultra-light mode"))
@@ -266,16 +402,20 @@ func (i *index) showStartView(firstStart bool) fyne.CanvasObject {
 			}
 		}
 
-		i.start(i.nodeConfig.path,
+		started := i.start(i.nodeConfig.path,
 			i.nodeConfig.password,
 			i.nodeConfig.welcomeMessage,
 			i.nodeConfig.natAddress,
 			i.nodeConfig.rpcEndpoint,
 			i.nodeConfig.swapEnable)
+		if started {
+			cancel()
+		}
 		content.Refresh()
 	})
 
 	backButton := widget.NewButton("Back", func() {
+		cancel()
 		if i.nodeConfig.swapEnable {
 			content.Objects = []fyne.CanvasObject{i.showRPCView()}
 		} else {
@@ -285,19 +425,18 @@ func (i *index) showStartView(firstStart bool) fyne.CanvasObject {
 	})
 	backButton.Importance = widget.WarningImportance
 	startButton.Importance = widget.HighImportance
+	updateStartButton()
 
 	bottomBox := container.NewVBox()
 	if overlayAddr != "" {
-		infoLabel := widget.NewLabel(fmt.Sprintf("Warning: cannot continue in light-mode until there is\nat least min %s (for Gas) and at least min %s available on\n address: %s",
-			NativeTokenSymbol, SwarmTokenSymbol, shortenHashOrAddress(overlayAddr)))
-		bottomBox.Add(infoLabel)
-		bottomBox.Add(i.copyButton(overlayAddr))
+		bottomBox.Add(container.NewHBox(widget.NewLabel(shortenHashOrAddress(overlayAddr)), i.copyButton(overlayAddr)))
+		bottomBox.Add(walletContent)
 	}
 	if firstStart {
 		bottomBox.Add(backButton)
 	}
 
-	advancedView := i.showAdvancedSettings()
+	advancedView := i.showAdvancedSettings(updateStartButton)
 	content.Objects = []fyne.CanvasObject{container.NewBorder(startButton, bottomBox, advancedView, nil)}
 	i.content = content
 	i.view = container.NewBorder(container.NewVBox(i.intro), nil, nil, nil, content)
@@ -306,13 +445,15 @@ func (i *index) showStartView(firstStart bool) fyne.CanvasObject {
 	return content
 }
 
-func (i *index) showAdvancedSettings() fyne.CanvasObject {
+// showAdvancedSettings returns the settings that can be changed before starting, onModeChanged is
+// called after the node mode changes.
+func (i *index) showAdvancedSettings(onModeChanged func()) fyne.CanvasObject {
 	hyperlink := widget.NewHyperlink("How to fund your node", nil)
 	err := hyperlink.SetURLFromString("https://docs.ethswarm.org/docs/installation/fund-your-node")
 	if err != nil {
 		i.logger.Log(fmt.Sprintf("failed to set hyperlink: %s", err.Error()))
 	}
-	modeDetail := container.NewVBox(i.getNodeModeRadio(), container.NewHBox(hyperlink))
+	modeDetail := container.NewVBox(i.getNodeModeRadio(onModeChanged), container.NewHBox(hyperlink))
 	modeSwitchItem := &widget.AccordionItem{
 		Title:  "Node mode",
 		Detail: modeDetail,
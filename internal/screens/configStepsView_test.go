@@ -0,0 +1,143 @@
+package screens
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethersphere/bee/v2/pkg/api"
+
+	"github.com/Solar-Punk-Ltd/swarm-mobile/internal/node/nodetest"
+)
+
+// findButton returns the first button with the text under o.
+func findButton(o fyne.CanvasObject, text string) *widget.Button {
+	switch o := o.(type) {
+	case *widget.Button:
+		if o.Text == text {
+			return o
+		}
+	case *fyne.Container:
+		for _, child := range o.Objects {
+			if b := findButton(child, text); b != nil {
+				return b
+			}
+		}
+	case *widget.Accordion:
+		for _, item := range o.Items {
+			if b := findButton(item.Detail, text); b != nil {
+				return b
+			}
+		}
+	}
+	return nil
+}
+
+// findRadio returns the first radio group under o.
+func findRadio(o fyne.CanvasObject) *widget.RadioGroup {
+	switch o := o.(type) {
+	case *widget.RadioGroup:
+		return o
+	case *fyne.Container:
+		for _, child := range o.Objects {
+			if r := findRadio(child); r != nil {
+				return r
+			}
+		}
+	case *widget.Accordion:
+		for _, item := range o.Items {
+			if r := findRadio(item.Detail); r != nil {
+				return r
+			}
+		}
+	}
+	return nil
+}
+
+func TestStartViewLightModeNeedsFunds(t *testing.T) {
+	i := newTestIndex(t, nodetest.New())
+	i.setPreference(overlayAddrPrefKey, common.HexToAddress("0x9858EfFD232B4033E47d90003D41EC34EcaEda94").String())
+	i.nodeConfig.password = "password"
+	i.nodeConfig.swapEnable = true
+	// unreachable, the balances stay unknown
+	i.nodeConfig.rpcEndpoint = "http://127.0.0.1:1"
+
+	content := i.showStartView(false)
+	startButton := findButton(content, "Start")
+	if startButton == nil {
+		t.Fatal("no start button")
+	}
+	if !startButton.Disabled() {
+		t.Fatal("light mode can start before the wallet balance is known")
+	}
+
+	findRadio(content).SetSelected(api.UltraLightMode.String())
+	if startButton.Disabled() {
+		t.Fatal("ultra-light mode cannot start")
+	}
+	findRadio(content).SetSelected(api.LightMode.String())
+	if !startButton.Disabled() {
+		t.Fatal("light mode can start after switching back")
+	}
+}
+
+func TestStartViewUltraLightMode(t *testing.T) {
+	i := newTestIndex(t, nodetest.New())
+	i.setPreference(overlayAddrPrefKey, common.HexToAddress("0x9858EfFD232B4033E47d90003D41EC34EcaEda94").String())
+	i.nodeConfig.rpcEndpoint = "http://127.0.0.1:1"
+
+	if findButton(i.showStartView(false), "Start").Disabled() {
+		t.Fatal("ultra-light mode cannot start")
+	}
+}
+
+// newFundedRPC returns a JSON-RPC endpoint of gnosis chain on which every balance is 1 xDAI and 1 xBZZ.
+func newFundedRPC(t *testing.T) string {
+	t.Helper()
+	word := fmt.Sprintf("0x%064x", big.NewInt(1e18))
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		result := map[string]string{
+			"eth_chainId":    "0x64",
+			"eth_getBalance": "0xde0b6b3a7640000",
+			// the token address and the token balance
+			"eth_call": word,
+		}[req.Method]
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":%q}`, req.ID, result)
+	}))
+	t.Cleanup(srv.Close)
+	return srv.URL
+}
+
+func TestStartViewLightModeFunded(t *testing.T) {
+	i := newTestIndex(t, nodetest.New())
+	i.setPreference(overlayAddrPrefKey, common.HexToAddress("0x9858EfFD232B4033E47d90003D41EC34EcaEda94").String())
+	i.nodeConfig.password = "password"
+	i.nodeConfig.swapEnable = true
+	i.nodeConfig.rpcEndpoint = newFundedRPC(t)
+
+	startButton := findButton(i.showStartView(false), "Start")
+	deadline := time.Now().Add(5 * time.Second)
+	for startButton.Disabled() {
+		if time.Now().After(deadline) {
+			t.Fatal("the start button is not enabled once the wallet is funded")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -2,79 +2,140 @@ package screens
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"strings"
 
 	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
 	"fyne.io/fyne/v2/widget"
 
+	"github.com/ethersphere/bee/v2/pkg/encryption"
 	"github.com/ethersphere/bee/v2/pkg/swarm"
 )
 
 func (i *index) showDownloadCard() *widget.Card {
 	dlForm := i.downloadForm()
-	return widget.NewCard("Download", "download content from swarm", dlForm)
+	pinsButton := i.listPinsButton(fyne.NewSize(350, 450))
+	return widget.NewCard("Download", "download content from swarm", container.NewVBox(dlForm, pinsButton))
 }
 
 func (i *index) downloadForm() *widget.Form {
 	hash := widget.NewEntry()
 	hash.SetPlaceHolder("Swarm Hash")
+	pinCheck := widget.NewCheck("Pin this", nil)
 	dlForm := &widget.Form{
 		Items: []*widget.FormItem{
-			{Text: "Swarm Hash", Widget: hash, HintText: "Swarm Hash"},
+			{Text: "Swarm Hash", Widget: hash, HintText: "Plain or encrypted reference"},
+			{Text: "", Widget: pinCheck, HintText: "Keep a copy of the content on this node"},
 		},
 		OnSubmit: func() {
-			dlAddr, err := swarm.ParseHexAddress(hash.Text)
-			if err != nil {
-				i.showError(err)
-				return
-			}
 			if hash.Text == "" {
 				i.showError(fmt.Errorf("please enter a hash"))
 				return
 			}
-			hashText := hash.Text
+			dlAddr, err := parseReference(hash.Text)
+			if err != nil {
+				i.showError(err)
+				return
+			}
+			pin := pinCheck.Checked
 			go func() {
-				i.showProgressWithMessage(fmt.Sprintf("Downloading %s", shortenHashOrAddress(hashText)))
-				ref, fileName, err := i.bl.GetBzz(context.Background(), dlAddr, nil, nil, nil)
-				if err != nil {
-					i.hideProgress()
-					i.showError(err)
+				if !i.download(i.Window, dlAddr) {
 					return
 				}
 				fyne.Do(func() {
 					hash.SetText("")
+					pinCheck.SetChecked(false)
 				})
-				data, err := io.ReadAll(ref)
-				if err != nil {
-					i.hideProgress()
-					i.showError(err)
-					return
+				if pin {
+					i.pin(dlAddr)
 				}
-				i.hideProgress()
-				fyne.Do(func() {
-					saveFile := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
-						if err != nil {
-							i.showError(err)
-							return
-						}
-						if writer == nil {
-							return
-						}
-						_, err = writer.Write(data)
-						if err != nil {
-							i.showError(err)
-							return
-						}
-						writer.Close()
-					}, i.Window)
-					saveFile.SetFileName(fileName)
-					saveFile.Show()
-				})
 			}()
 		},
 	}
 
 	return dlForm
 }
+
+// parseReference parses a plain (64 hex characters) or encrypted (128 hex characters) reference.
+// Encrypted references carry the decryption key, so they are downloaded the same way as plain ones.
+func parseReference(s string) (swarm.Address, error) {
+	addr, err := swarm.ParseHexAddress(strings.TrimSpace(s))
+	if err != nil {
+		return swarm.ZeroAddress, err
+	}
+	if l := len(addr.Bytes()); l != swarm.HashSize && l != encryption.ReferenceSize {
+		return swarm.ZeroAddress, fmt.Errorf("reference must be %d or %d hex characters long", swarm.HashSize*2, encryption.ReferenceSize*2)
+	}
+	return addr, nil
+}
+
+func isEncryptedReference(s string) bool {
+	return len(s) == encryption.ReferenceSize*2
+}
+
+// download resolves the manifest of addr and asks for the destination of its content, with the
+// dialogs on w. It reports whether the manifest could be resolved.
+func (i *index) download(w fyne.Window, addr swarm.Address) bool {
+	// only the manifest is resolved here, the content is streamed after the destination is chosen
+	i.showProgressIn(w, fmt.Sprintf("Downloading %s", shortenHashOrAddress(addr.String())))
+	ctx, cancel := context.WithCancel(i.ctx)
+	ref, fileName, err := i.node.GetBzz(ctx, addr)
+	i.hideProgress()
+	if err != nil {
+		cancel()
+		i.showErrorIn(w, err)
+		return false
+	}
+	fyne.Do(func() {
+		saveFile := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+			if err != nil {
+				cancel()
+				i.showErrorIn(w, err)
+				return
+			}
+			if writer == nil {
+				cancel()
+				return
+			}
+			go i.saveDownload(ctx, cancel, w, ref, writer)
+		}, w)
+		saveFile.SetFileName(fileName)
+		saveFile.Show()
+	})
+	return true
+}
+
+// saveDownload pipes the swarm reader into the writer, showing the progress until it is done or cancelled.
+func (i *index) saveDownload(ctx context.Context, cancel context.CancelFunc, w fyne.Window, ref io.Reader, writer fyne.URIWriteCloser) {
+	defer cancel()
+	size := int64(-1)
+	if sized, ok := ref.(interface{ Size() int64 }); ok {
+		size = sized.Size()
+	}
+
+	reader := newCountingReader(ctx, ref)
+	progress := i.showTransferProgress(w, fmt.Sprintf("Saving %s", writer.URI().Name()), size, reader.Count, cancel)
+	_, err := io.Copy(writer, reader)
+	progress.hide()
+	closeErr := writer.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		if delErr := storage.Delete(writer.URI()); delErr != nil {
+			i.logger.Log(fmt.Sprintf("failed to remove incomplete download: %s", delErr.Error()))
+		}
+		if errors.Is(err, context.Canceled) {
+			i.logger.Log(fmt.Sprintf("download of %s cancelled", writer.URI().Name()))
+			return
+		}
+		i.showErrorIn(w, err)
+		return
+	}
+	i.logger.Log(fmt.Sprintf("downloaded %s to %s", formatBytes(reader.Count()), writer.URI().String()))
+}
@@ -0,0 +1,214 @@
+package screens
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/Solar-Punk-Ltd/swarm-mobile/internal/history"
+)
+
+const defaultIndexDocument = "index.html"
+
+type folderFile struct {
+	uri  fyne.URI
+	path string
+	size int64
+}
+
+func (i *index) uploadFolderButton() *widget.Button {
+	return widget.NewButton("Upload folder", func() {
+		fd := dialog.NewFolderOpen(func(dir fyne.ListableURI, err error) {
+			if err != nil {
+				i.showError(err)
+				return
+			}
+			if dir == nil {
+				return
+			}
+			i.showFolderOptions(dir)
+		}, i.Window)
+		fd.Show()
+	})
+}
+
+func (i *index) showFolderOptions(dir fyne.ListableURI) {
+	indexEntry := widget.NewEntry()
+	indexEntry.SetText(defaultIndexDocument)
+	errorEntry := widget.NewEntry()
+	errorEntry.SetPlaceHolder("404.html")
+	encryptCheck := widget.NewCheck("Encrypt", nil)
+	pinCheck := widget.NewCheck("Pin", nil)
+	items := []*widget.FormItem{
+		{Text: "Index document", Widget: indexEntry, HintText: "served at the root of the manifest"},
+		{Text: "Error document", Widget: errorEntry, HintText: "optional"},
+		{Text: "", Widget: encryptCheck},
+		{Text: "", Widget: pinCheck},
+	}
+
+	d := dialog.NewForm(fmt.Sprintf("Upload %s", dir.Name()), "Upload", "Cancel", items, func(ok bool) {
+		if !ok {
+			return
+		}
+		go i.uploadFolder(dir, indexEntry.Text, errorEntry.Text, encryptCheck.Checked, pinCheck.Checked)
+	}, i.Window)
+	parentSize := i.Window.Canvas().Size()
+	d.Resize(fyne.NewSize(parentSize.Width*90/100, 0))
+	d.Show()
+}
+
+func (i *index) uploadFolder(dir fyne.ListableURI, indexDocument, errorDocument string, encrypt, pin bool) {
+	batchID := i.getPreferenceString(batchPrefKey)
+	if batchID == "" {
+		i.showError(fmt.Errorf("please select a batch of stamp"))
+		return
+	}
+
+	i.showProgressWithMessage(fmt.Sprintf("Reading %s", dir.Name()))
+	files, err := listFolder(dir, "")
+	i.hideProgress()
+	if err != nil {
+		i.showError(fmt.Errorf("failed to read folder: %w", err))
+		return
+	}
+	if len(files) == 0 {
+		i.showError(fmt.Errorf("folder %s is empty", dir.Name()))
+		return
+	}
+
+	if err := checkDocuments(files, indexDocument, errorDocument); err != nil {
+		i.showError(err)
+		return
+	}
+	total := int64(0)
+	for _, f := range files {
+		total += f.size
+	}
+
+	ctx, cancel := context.WithCancel(i.ctx)
+	defer cancel()
+	counter := newCountingReader(ctx, nil)
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(writeTar(pw, files, counter))
+	}()
+
+	progress := i.showTransferProgress(i.Window, fmt.Sprintf("Uploading %s", dir.Name()), total, counter.Count, cancel)
+	ref, err := i.node.AddDirBzz(ctx, batchID, dir.Name(), indexDocument, errorDocument, encrypt, pr)
+	// unblock the tar writer if the upload stopped before reading everything
+	pr.CloseWithError(err)
+	progress.hide()
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			i.logger.Log(fmt.Sprintf("upload of %s cancelled", dir.Name()))
+			return
+		}
+		i.showError(err)
+		return
+	}
+
+	i.logger.Log(fmt.Sprintf("manifest reference of the uploaded folder: %s", ref.String()))
+	err = i.addUpload(history.Item{
+		Name:      dir.Name(),
+		Reference: ref.String(),
+		Timestamp: time.Now(),
+		Size:      total,
+		Mimetype:  history.ManifestMimetype,
+		BatchID:   batchID,
+	})
+	if err != nil {
+		i.showError(err)
+		return
+	}
+	i.showUploadSuccess(ref.String())
+	if pin {
+		i.pin(ref)
+	}
+}
+
+// checkDocuments checks that the index and error documents, if set, are files of the folder.
+func checkDocuments(files []folderFile, indexDocument, errorDocument string) error {
+	for _, doc := range []struct{ kind, path string }{{"index", indexDocument}, {"error", errorDocument}} {
+		if doc.path == "" {
+			continue
+		}
+		if !slices.ContainsFunc(files, func(f folderFile) bool { return f.path == doc.path }) {
+			return fmt.Errorf("%s document %s is not in the folder", doc.kind, doc.path)
+		}
+	}
+	return nil
+}
+
+// listFolder returns the files under dir recursively with their paths relative to dir.
+func listFolder(dir fyne.ListableURI, prefix string) ([]folderFile, error) {
+	children, err := dir.List()
+	if err != nil {
+		return nil, err
+	}
+
+	files := []folderFile{}
+	for _, child := range children {
+		childPath := path.Join(prefix, child.Name())
+		if ok, _ := storage.CanList(child); ok {
+			lister, err := storage.ListerForURI(child)
+			if err != nil {
+				return nil, err
+			}
+			sub, err := listFolder(lister, childPath)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, sub...)
+			continue
+		}
+
+		size := uriSize(child)
+		if size < 0 {
+			// tar headers need the size up front, read the file once to get it
+			size, err = readSize(child)
+			if err != nil {
+				return nil, err
+			}
+		}
+		files = append(files, folderFile{uri: child, path: childPath, size: size})
+	}
+	return files, nil
+}
+
+// writeTar streams the files into w as a tar archive, reading them through counter.
+func writeTar(w io.Writer, files []folderFile, counter *countingReader) error {
+	tw := tar.NewWriter(w)
+	for _, f := range files {
+		err := tw.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     f.path,
+			Mode:     0o644,
+			Size:     f.size,
+		})
+		if err != nil {
+			return err
+		}
+
+		reader, err := storage.Reader(f.uri)
+		if err != nil {
+			return err
+		}
+		counter.r = reader
+		_, err = io.CopyN(tw, counter, f.size)
+		reader.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", f.path, err)
+		}
+	}
+	return tw.Close()
+}
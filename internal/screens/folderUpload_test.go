@@ -0,0 +1,119 @@
+package screens
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/storage"
+
+	"github.com/Solar-Punk-Ltd/swarm-mobile/internal/history"
+	"github.com/Solar-Punk-Ltd/swarm-mobile/internal/node/nodetest"
+)
+
+// testFolder returns a folder with an index document at its root and an error document in a sub folder.
+func testFolder(t *testing.T) fyne.ListableURI {
+	t.Helper()
+	dir := filepath.Join(t.TempDir(), "site")
+	for name, content := range map[string]string{
+		"index.html":        "<h1>site</h1>",
+		"errors/404.html":   "not found",
+		"assets/style.css":  "body {}",
+		"assets/empty.json": "",
+	} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	lister, err := storage.ListerForURI(storage.NewFileURI(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return lister
+}
+
+func TestCheckDocuments(t *testing.T) {
+	files := []folderFile{{path: "index.html"}, {path: "errors/404.html"}}
+	tests := []struct {
+		name          string
+		indexDocument string
+		errorDocument string
+		wantErr       bool
+	}{
+		{"both documents", "index.html", "errors/404.html", false},
+		{"no documents", "", "", false},
+		{"missing index document", "home.html", "", true},
+		{"missing error document", "index.html", "404.html", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkDocuments(files, tt.indexDocument, tt.errorDocument); (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v", err)
+			}
+		})
+	}
+}
+
+func TestUploadFolder(t *testing.T) {
+	n := nodetest.New()
+	i := newTestIndex(t, n)
+	i.openHistory()
+	t.Cleanup(func() { i.history.Close() })
+	i.setPreference(batchPrefKey, "ff")
+
+	i.uploadFolder(testFolder(t), "index.html", "errors/404.html", false, false)
+	if len(n.Files) != 1 {
+		t.Fatalf("got %d uploads, want 1", len(n.Files))
+	}
+	var upload nodetest.File
+	for _, f := range n.Files {
+		upload = f
+	}
+	if upload.Name != "site" || upload.IndexDocument != "index.html" || upload.ErrorDocument != "errors/404.html" || upload.BatchID != "ff" {
+		t.Errorf("got upload %+v", upload)
+	}
+	tr := tar.NewReader(bytes.NewReader(upload.Data))
+	paths := map[string]bool{}
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		paths[h.Name] = true
+	}
+	if len(paths) != 4 || !paths["index.html"] || !paths["errors/404.html"] || !paths["assets/empty.json"] {
+		t.Errorf("got files %v", paths)
+	}
+
+	items, err := i.history.List(0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || !items[0].IsManifest() || items[0].Mimetype != history.ManifestMimetype || items[0].Size != 29 {
+		t.Errorf("got history %+v", items)
+	}
+}
+
+func TestUploadFolderMissingDocument(t *testing.T) {
+	n := nodetest.New()
+	i := newTestIndex(t, n)
+	i.openHistory()
+	t.Cleanup(func() { i.history.Close() })
+	i.setPreference(batchPrefKey, "ff")
+
+	i.uploadFolder(testFolder(t), "index.html", "404.html", false, false)
+	if len(n.Files) != 0 {
+		t.Fatal("the folder was uploaded without its error document")
+	}
+}
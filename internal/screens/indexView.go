@@ -1,31 +1,33 @@
 package screens
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"path/filepath"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/dialog"
 	"fyne.io/fyne/v2/widget"
 
-	beelite "github.com/Solar-Punk-Ltd/bee-lite"
 	"github.com/ethersphere/bee/v2/pkg/api"
+
+	"github.com/Solar-Punk-Ltd/swarm-mobile/internal/history"
+	"github.com/Solar-Punk-Ltd/swarm-mobile/internal/node"
 )
 
 const (
-	TestnetChainID        = 5 //testnet
-	MainnetChainID        = 100
-	MainnetNetworkID      = uint64(1)
 	NativeTokenSymbol     = "xDAI"
 	SwarmTokenSymbol      = "xBZZ"
-	defaultRPC            = "https://gnosis.publicnode.com"
-	defaultWelcomeMsg     = "Welcome from Swarm Mobile by Solar Punk"
-	infoLogLevel          = "3"
-	defaultDepth          = "21"
+	defaultRPC            = node.DefaultRPCEndpoint
+	defaultWelcomeMsg     = node.DefaultWelcomeMessage
+	defaultCapacity       = "1"
+	defaultDuration       = "30"
 	defaultAmount         = "500000000"
 	defaultImmutable      = true
-	passwordPrefKey       = "password"
+	passwordPrefKey       = "password" // no longer stored, removed from older installs
 	welcomeMessagePrefKey = "welcomeMessage"
 	swapEnablePrefKey     = "swapEnable"
 	natAddressPrefKey     = "natAddress"
@@ -34,16 +36,10 @@ const (
 	batchPrefKey          = "batch"
 	uploadsPrefKey        = "uploads"
 	overlayAddrPrefKey    = "overlayAddress"
-)
-
-var (
-	MainnetBootnodes = []string{
-		"/dnsaddr/mainnet.ethswarm.org",
-	}
-
-	TestnetBootnodes = []string{
-		"/dnsaddr/testnet.ethswarm.org",
-	}
+	networkPrefKey        = "network"
+	networkIDPrefKey      = "networkID"
+	bootnodesPrefKey      = "bootnodes"
+	historyDir            = "history"
 )
 
 type logger struct{}
@@ -59,14 +55,27 @@ func (*logger) Log(s string) {
 
 type index struct {
 	fyne.Window
-	app        fyne.App
+	app    fyne.App
+	ctx    context.Context
+	cancel context.CancelFunc
+	// menuCancel stops the background refreshes of the current menu view.
+	menuCancel context.CancelFunc
+	main       *fyne.Container
 	view       *fyne.Container
 	content    *fyne.Container
 	intro      *widget.Label
 	progress   dialog.Dialog
-	bl         *beelite.Beelite
+	node       node.Node
+	history    *history.Store
 	logger     *logger
 	nodeConfig *nodeConfig
+	appLock    *appLock
+	// childWindows are the open secondary windows, closed by the app lock.
+	childWindows []fyne.Window
+	profile      profile
+	rootPath     string
+	// shuttingDown is set by the first close of the window, the node is stopped once.
+	shuttingDown bool
 }
 
 func Make(a fyne.App, w fyne.Window) fyne.CanvasObject {
@@ -76,114 +85,97 @@ func Make(a fyne.App, w fyne.Window) fyne.CanvasObject {
 		intro:      widget.NewLabel(""),
 		logger:     &logger{},
 		nodeConfig: &nodeConfig{},
+		node:       node.New(),
 	}
+	i.ctx, i.cancel = context.WithCancel(context.Background())
 	i.intro.Wrapping = fyne.TextWrapWord
 	i.printAppInfo()
+	w.SetCloseIntercept(i.shutdown)
 
 	i.nodeConfig.isKeyStoreMem = a.Driver().Device().IsBrowser()
 	if i.nodeConfig.isKeyStoreMem {
 		i.logger.Log("Running in browser, using in-memory keystore")
 	} else {
-		i.nodeConfig.path = a.Storage().RootURI().Path()
-		i.logger.Log("App datadir path: " + i.nodeConfig.path)
+		i.rootPath = a.Storage().RootURI().Path()
+		i.logger.Log("App datadir path: " + i.rootPath)
 	}
 
-	i.nodeConfig.password = i.getPreferenceString(passwordPrefKey)
-	if i.nodeConfig.password != "" && i.getPreferenceString(overlayAddrPrefKey) != "" {
-		i.nodeConfig.welcomeMessage = i.getPreferenceString(welcomeMessagePrefKey)
-		i.nodeConfig.natAddress = i.getPreferenceString(natAddressPrefKey)
-		i.nodeConfig.rpcEndpoint = i.getPreferenceString(rpcEndpointPrefKey)
-		i.nodeConfig.swapEnable = i.getPreferenceBool(swapEnablePrefKey)
-
-		i.view = container.NewBorder(container.NewVBox(i.intro), nil, nil, nil, container.NewStack(i.showStartView(false)))
-		i.view.Refresh()
-		return i.view
+	i.main = container.NewStack()
+	profiles := i.loadProfiles()
+	if len(profiles) > 1 {
+		i.showProfilesView()
+	} else {
+		i.openProfile(profiles[0])
 	}
-
-	i.showPasswordView()
-
-	i.view = container.NewBorder(container.NewVBox(i.intro), nil, nil, nil, i.content)
-	return i.view
+	return i.newAppLock(i.main)
 }
 
-func (i *index) start(path, password, welcomeMessage, natAddress, rpcEndpoint string, swapEnable bool) {
+// start starts the node and shows the menu. It reports whether the node started.
+func (i *index) start(path, password, welcomeMessage, natAddress, rpcEndpoint string, swapEnable bool) bool {
 	if password == "" {
 		i.showError(fmt.Errorf("password cannot be blank"))
-		return
+		return false
 	}
 	i.showProgressWithMessage("Starting Bee")
 
 	err := i.initSwarm(path, welcomeMessage, password, natAddress, rpcEndpoint, swapEnable)
 	i.hideProgress()
 	if err != nil {
-		if i.bl != nil {
-			i.showErrorWithAddr(i.bl.OverlayEthAddress(), err)
-		} else {
-			i.showError(err)
-		}
-		return
+		i.showError(err)
+		return false
 	}
 
 	if swapEnable {
-		if i.bl.BeeNodeMode() != api.LightMode {
-			i.showError(fmt.Errorf("swap is enabled but the current node mode is: %s", i.bl.BeeNodeMode()))
-			return
+		if i.node.BeeNodeMode() != api.LightMode {
+			i.showError(fmt.Errorf("swap is enabled but the current node mode is: %s", i.node.BeeNodeMode()))
+			return false
 		}
-	} else if i.bl.BeeNodeMode() != api.UltraLightMode {
-		i.showError(fmt.Errorf("swap disabled but the current node mode is: %s", i.bl.BeeNodeMode()))
-		return
+	} else if i.node.BeeNodeMode() != api.UltraLightMode {
+		i.showError(fmt.Errorf("swap disabled but the current node mode is: %s", i.node.BeeNodeMode()))
+		return false
 	}
 
 	i.setPreference(welcomeMessagePrefKey, welcomeMessage)
 	i.setPreference(swapEnablePrefKey, swapEnable)
 	i.setPreference(natAddressPrefKey, natAddress)
 	i.setPreference(rpcEndpointPrefKey, rpcEndpoint)
+	i.saveNetwork(i.nodeConfig.network)
 	i.loadMenuView()
 	i.intro.SetText("")
 	i.intro.Hide()
+	return true
 }
 
 func (i *index) initSwarm(dataDir, welcomeMessage, password, natAddress, rpcEndpoint string, swapEnable bool) error {
 	i.logger.Log(welcomeMessage)
 
-	lo := &beelite.LiteOptions{
-		FullNodeMode:             false,
-		BootnodeMode:             false,
-		Bootnodes:                MainnetBootnodes,
-		DataDir:                  dataDir,
-		WelcomeMessage:           welcomeMessage,
-		BlockchainRpcEndpoint:    rpcEndpoint,
-		SwapInitialDeposit:       "0",
-		PaymentThreshold:         "100000000",
-		SwapEnable:               swapEnable,
-		ChequebookEnable:         true,
-		UsePostageSnapshot:       false,
-		Mainnet:                  true,
-		NetworkID:                MainnetNetworkID,
-		NATAddr:                  natAddress,
-		CacheCapacity:            32 * 1024 * 1024,
-		DBOpenFilesLimit:         50,
-		DBWriteBufferSize:        32 * 1024 * 1024,
-		DBBlockCacheCapacity:     32 * 1024 * 1024,
-		DBDisableSeeksCompaction: false,
-		RetrievalCaching:         true,
-	}
-
-	bl, err := beelite.Start(lo, password, infoLogLevel)
+	err := i.node.Start(&node.Options{
+		DataDir:        dataDir,
+		Password:       password,
+		WelcomeMessage: welcomeMessage,
+		NATAddress:     natAddress,
+		RPCEndpoint:    rpcEndpoint,
+		SwapEnable:     swapEnable,
+		Network:        i.nodeConfig.network,
+	})
 	if err != nil {
 		return err
 	}
 
-	i.setPreference(passwordPrefKey, password)
-	i.setPreference(overlayAddrPrefKey, bl.OverlayEthAddress().String())
-	i.bl = bl
-	return err
+	i.setPreference(overlayAddrPrefKey, i.node.OverlayEthAddress().String())
+	return nil
 }
 
 func (i *index) loadMenuView() {
 	// only show certain views if the node mode is NOT ultra-light
-	ultraLightMode := i.bl.BeeNodeMode() == api.UltraLightMode
-	infoCard := i.showInfoCard(ultraLightMode)
+	ultraLightMode := i.node.BeeNodeMode() == api.UltraLightMode
+	// the panels of the replaced menu stop refreshing
+	if i.menuCancel != nil {
+		i.menuCancel()
+	}
+	ctx, cancel := context.WithCancel(i.ctx)
+	i.menuCancel = cancel
+	infoCard := i.showInfoCard(ctx, ultraLightMode)
 	menuContent := container.NewGridWithColumns(1, infoCard)
 	if !ultraLightMode {
 		uploadCard := i.showUploadCard()
@@ -200,3 +192,96 @@ func (i *index) loadMenuView() {
 	}
 	i.content.Refresh()
 }
+
+// openHistory opens the upload history in the app datadir and migrates the uploads saved in the preferences.
+// In the browser there is no datadir, the history is kept in memory and saved to the preferences, which are
+// backed by the local storage, on every change. The in-memory history used when the datadir cannot be opened
+// is saved the same way. The history of the previous profile is closed.
+func (i *index) openHistory() {
+	if i.history != nil {
+		if err := i.history.Close(); err != nil {
+			i.logger.Log(fmt.Sprintf("failed to close upload history: %s", err.Error()))
+		}
+		i.history = nil
+	}
+	dir := ""
+	if !i.nodeConfig.isKeyStoreMem {
+		dir = filepath.Join(i.nodeConfig.path, historyDir)
+	}
+	inMemory := dir == ""
+	store, err := history.Open(dir)
+	if err != nil {
+		i.logger.Log(fmt.Sprintf("%s, using in-memory upload history", err.Error()))
+		inMemory = true
+		store, err = history.Open("")
+		if err != nil {
+			i.logger.Log(err.Error())
+			return
+		}
+	}
+	i.history = store
+
+	// the preferences helpers are disabled in the browser, the history uses the preferences directly
+	prefs := i.app.Preferences()
+	key := i.profile.prefKey(uploadsPrefKey)
+	uploads := prefs.String(key)
+	if uploads != "" {
+		n, err := i.history.Import([]byte(uploads))
+		if err != nil {
+			i.logger.Log(fmt.Sprintf("failed to load uploads from preferences: %s", err.Error()))
+			return
+		}
+		// the uploads are only safe to remove once they are in the datadir
+		if !inMemory {
+			prefs.SetString(key, "")
+			i.logger.Log(fmt.Sprintf("Migrated %d uploads from preferences", n))
+		}
+	}
+	if inMemory {
+		store.SetOnChange(func() {
+			data, err := store.Export()
+			if err != nil {
+				i.logger.Log(fmt.Sprintf("failed to save upload history: %s", err.Error()))
+				return
+			}
+			prefs.SetString(key, string(data))
+		})
+	}
+}
+
+// shutdown stops the background refreshes and the node before closing the window.
+func (i *index) shutdown() {
+	if i.shuttingDown {
+		return
+	}
+	i.shuttingDown = true
+	progressLabel := widget.NewLabel("Stopping background tasks")
+	progress := dialog.NewCustomWithoutButtons("Shutting down", container.NewVBox(progressLabel, widget.NewProgressBarInfinite()), i.Window)
+	progress.Show()
+	i.cancel()
+
+	go func() {
+		fyne.Do(func() {
+			progressLabel.SetText("Stopping Bee")
+		})
+		ctx, cancel := context.WithTimeout(context.Background(), node.ShutdownTimeout)
+		defer cancel()
+		err := i.node.Shutdown(ctx)
+		switch {
+		case errors.Is(err, node.ErrNotStarted):
+		case err != nil:
+			i.logger.Log(fmt.Sprintf("failed to stop node: %s", err.Error()))
+		default:
+			i.logger.Log("Bee stopped")
+		}
+		if i.history != nil {
+			if err := i.history.Close(); err != nil {
+				i.logger.Log(fmt.Sprintf("failed to close upload history: %s", err.Error()))
+			}
+		}
+		fyne.Do(func() {
+			progress.Hide()
+			i.Window.Close()
+		})
+	}()
+}
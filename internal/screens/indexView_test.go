@@ -0,0 +1,181 @@
+package screens
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethersphere/bee/v2/pkg/api"
+
+	"github.com/Solar-Punk-Ltd/swarm-mobile/internal/history"
+	"github.com/Solar-Punk-Ltd/swarm-mobile/internal/node"
+	"github.com/Solar-Punk-Ltd/swarm-mobile/internal/node/nodetest"
+)
+
+// newTestIndex returns the screens of the app on a test window, backed by the fake node n.
+func newTestIndex(t *testing.T, n *nodetest.Node) *index {
+	t.Helper()
+	a := test.NewTempApp(t)
+	dataDir := t.TempDir()
+	i := &index{
+		Window:     a.NewWindow("test"),
+		app:        a,
+		intro:      widget.NewLabel(""),
+		logger:     &logger{},
+		nodeConfig: &nodeConfig{path: dataDir, network: node.Mainnet},
+		node:       n,
+		content:    container.NewStack(),
+		profile:    profile{ID: defaultProfileID, Name: "Default"},
+		rootPath:   dataDir,
+	}
+	i.appLock = &appLock{index: i}
+	i.ctx, i.cancel = context.WithCancel(context.Background())
+	t.Cleanup(i.cancel)
+	return i
+}
+
+func TestStart(t *testing.T) {
+	n := nodetest.New()
+	n.Overlay = common.HexToAddress("0x9858EfFD232B4033E47d90003D41EC34EcaEda94")
+	i := newTestIndex(t, n)
+
+	if !i.start(i.nodeConfig.path, "password", "hello", "", "", false) {
+		t.Fatal("the node did not start")
+	}
+	if n.Options.Password != "password" || n.Options.WelcomeMessage != "hello" || n.Options.Network.Name != node.MainnetNetworkName {
+		t.Errorf("unexpected options %+v", n.Options)
+	}
+	if got := i.getPreferenceString(overlayAddrPrefKey); got != n.Overlay.String() {
+		t.Errorf("got overlay preference %q, want %q", got, n.Overlay.String())
+	}
+	if got := i.getPreferenceString(welcomeMessagePrefKey); got != "hello" {
+		t.Errorf("got welcome message preference %q", got)
+	}
+	if got := i.getPreferenceString(passwordPrefKey); got != "" {
+		t.Error("the password is saved in the preferences")
+	}
+	if len(i.content.Objects) == 0 {
+		t.Error("the menu is not shown")
+	}
+}
+
+func TestStartFailures(t *testing.T) {
+	tests := []struct {
+		name       string
+		password   string
+		swapEnable bool
+		setup      func(n *nodetest.Node)
+	}{
+		{"blank password", "", false, func(*nodetest.Node) {}},
+		{"start error", "password", false, func(n *nodetest.Node) { n.StartErr = errors.New("no peers") }},
+		{"light mode without swap", "password", false, func(n *nodetest.Node) { n.Mode = api.LightMode }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := nodetest.New()
+			tt.setup(n)
+			i := newTestIndex(t, n)
+			if i.start(i.nodeConfig.path, tt.password, "hello", "", "", tt.swapEnable) {
+				t.Fatal("start succeeded")
+			}
+			if got := i.getPreferenceString(welcomeMessagePrefKey); got != "" {
+				t.Errorf("the settings of a failed start are saved: %q", got)
+			}
+		})
+	}
+}
+
+func TestOpenHistoryMigration(t *testing.T) {
+	i := newTestIndex(t, nodetest.New())
+	i.setPreference(uploadsPrefKey, `[{"Name":"a.txt","Reference":"aaaa","Timestamp":"2024-05-01T10:00:00Z"}]`)
+
+	i.openHistory()
+	t.Cleanup(func() { i.history.Close() })
+	if count, err := i.history.Count(); err != nil || count != 1 {
+		t.Fatalf("got %d migrated uploads, %v", count, err)
+	}
+	if got := i.getPreferenceString(uploadsPrefKey); got != "" {
+		t.Errorf("the migrated uploads are kept in the preferences: %q", got)
+	}
+}
+
+// TestOpenHistoryBrowser checks that the history outlives a reload of the page in browser mode.
+func TestOpenHistoryBrowser(t *testing.T) {
+	i := newTestIndex(t, nodetest.New())
+	i.nodeConfig.isKeyStoreMem = true
+
+	i.openHistory()
+	if err := i.addUpload(history.Item{Name: "a.txt", Reference: "aaaa", Timestamp: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	i.openHistory()
+	t.Cleanup(func() { i.history.Close() })
+	items, err := i.history.List(0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || items[0].Reference != "aaaa" {
+		t.Fatalf("got history %+v after reopening", items)
+	}
+}
+
+// TestOpenHistoryFallback checks that the uploads are kept in the preferences while the datadir cannot be opened.
+func TestOpenHistoryFallback(t *testing.T) {
+	i := newTestIndex(t, nodetest.New())
+	// a file where the history directory should be
+	if err := os.WriteFile(filepath.Join(i.nodeConfig.path, historyDir), nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	legacy := `[{"Name":"a.txt","Reference":"aaaa","Timestamp":"2024-05-01T10:00:00Z"}]`
+	i.setPreference(uploadsPrefKey, legacy)
+
+	i.openHistory()
+	t.Cleanup(func() { i.history.Close() })
+	if count, err := i.history.Count(); err != nil || count != 1 {
+		t.Fatalf("got %d uploads, %v", count, err)
+	}
+	if got := i.getPreferenceString(uploadsPrefKey); got == "" {
+		t.Fatal("the uploads are removed from the preferences while only in memory")
+	}
+
+	if err := i.addUpload(history.Item{Name: "b.txt", Reference: "bbbb", Timestamp: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	i.openHistory()
+	if count, err := i.history.Count(); err != nil || count != 2 {
+		t.Fatalf("got %d uploads after reopening, %v", count, err)
+	}
+}
+
+func TestShutdownOnce(t *testing.T) {
+	n := nodetest.New()
+	i := newTestIndex(t, n)
+	if err := n.Start(&node.Options{}); err != nil {
+		t.Fatal(err)
+	}
+
+	i.shutdown()
+	i.shutdown()
+	if i.ctx.Err() == nil {
+		t.Fatal("the background tasks are not stopped")
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for n.Started() && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	// a second shutdown would have run by now
+	time.Sleep(100 * time.Millisecond)
+	n.Mu.Lock()
+	defer n.Mu.Unlock()
+	if n.Shutdowns != 1 {
+		t.Errorf("got %d shutdowns, want 1", n.Shutdowns)
+	}
+}
@@ -1,6 +1,7 @@
 package screens
 
 import (
+	"context"
 	"encoding/hex"
 	"fmt"
 	"math/big"
@@ -11,50 +12,74 @@ import (
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/data/binding"
 	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
+
+	"github.com/Solar-Punk-Ltd/swarm-mobile/internal/node"
+)
+
+const (
+	unitMB = "MB"
+	unitGB = "GB"
 )
 
-func (i *index) showInfoCard(ultraLightMode bool) *widget.Card {
+var capacityUnits = map[string]int64{
+	unitMB: 1 << 20,
+	unitGB: 1 << 30,
+}
+
+// showInfoCard returns the info card, its panels are refreshed until ctx is done.
+func (i *index) showInfoCard(ctx context.Context, ultraLightMode bool) *widget.Card {
+	refresher := i.newInfoRefresher(!ultraLightMode)
 	addressContent := i.addressContent()
 	walletDataButton := i.walletDataButton()
 	infoContent := container.NewVBox(addressContent)
 	if !ultraLightMode {
 		batchRadio := i.batchRadio()
-		stampsContent := i.stampsContent(batchRadio)
-		buyBatchButton := i.buyBatchButton(batchRadio)
-		balanceContent := i.balanceContent()
-		infoContent = container.NewVBox(addressContent, balanceContent, stampsContent, buyBatchButton)
+		stampsContent := i.stampsContent(batchRadio, refresher.stamps)
+		batchesButton := i.listBatchesButton(fyne.NewSize(350, 450))
+		buyBatchButton := i.buyBatchButton(refresher.refresh)
+		balanceContent := widget.NewLabelWithData(refresher.balance)
+		chequebookContent := i.chequebookContent(refresher.refresh)
+		settlementsButton := i.listSettlementsButton(fyne.NewSize(350, 450))
+		_, walletContent := i.newWalletPanel(ctx, i.node.OverlayEthAddress(), nil)
+		infoContent = container.NewVBox(addressContent, walletContent, balanceContent, chequebookContent, settlementsButton, stampsContent, batchesButton, buyBatchButton)
 	}
+	infoContent.Add(i.listPeersButton(fyne.NewSize(350, 450)))
 	infoContent.Add(walletDataButton)
+	infoContent.Add(i.exportKeysButton())
+	infoContent.Add(i.changePasswordButton())
+	infoContent.Add(i.appLockButton())
 
-	infoCard := widget.NewCard("Info",
-		fmt.Sprintf("Connected with %d peers", i.bl.ConnectedPeerCount()), infoContent)
-
-	// auto reload
-	go func() {
-		for {
-			time.Sleep(time.Second * 5)
-			if i.bl != nil {
-				fyne.Do(func() {
-					infoCard.SetSubTitle(fmt.Sprintf("Connected with %d peers", i.bl.ConnectedPeerCount()))
-				})
-			}
+	refreshButton := widget.NewButtonWithIcon("", theme.ViewRefreshIcon(), refresher.refresh)
+	statusLabel := widget.NewLabelWithData(refresher.status)
+	statusLabel.Importance = widget.LowImportance
+	infoContent.Add(container.NewBorder(nil, nil, nil, refreshButton, statusLabel))
+
+	infoCard := widget.NewCard("Info", "", infoContent)
+	refresher.peers.AddListener(binding.NewDataListener(func() {
+		peers, err := refresher.peers.Get()
+		if err == nil {
+			infoCard.SetSubTitle(peers)
 		}
-	}()
+	}))
+	go refresher.run(ctx)
 
 	return infoCard
 }
 
 func (i *index) walletDataButton() *widget.Button {
 	button := widget.NewButton("Read wallet data ", func() {
-		key, err := i.readAppData("/keys/swarm.key")
-		if err != nil {
-			i.showError(fmt.Errorf("failed to read swarm.key: %s", err.Error()))
-			return
-		}
+		i.authenticate(i.Window, "Read wallet data", func() {
+			key, err := i.readAppData("/keys/swarm.key")
+			if err != nil {
+				i.showError(fmt.Errorf("failed to read swarm.key: %s", err.Error()))
+				return
+			}
 
-		d := dialog.NewCustomConfirm("Wallet data", "Ok", "Cancel", i.copyDialog("Do not share this with anyone!\nCopy and save your key file.", key), func(b bool) {}, i.Window)
-		d.Show()
+			d := dialog.NewCustomConfirm("Wallet data", "Ok", "Cancel", i.copyDialog("Do not share this with anyone!\nCopy and save your key file.", key), func(b bool) {}, i.Window)
+			d.Show()
+		})
 	})
 	button.Importance = widget.DangerImportance
 
@@ -62,54 +87,33 @@ func (i *index) walletDataButton() *widget.Button {
 }
 
 func (i *index) addressContent() *fyne.Container {
-	addrCopyButton := i.copyButton(i.bl.OverlayEthAddress().String())
+	addrCopyButton := i.copyButton(i.node.OverlayEthAddress().String())
 	addrHeader := container.NewHBox(widget.NewLabel("Overlay address:"))
 	addr := container.NewHBox(
-		widget.NewLabel(i.bl.OverlayEthAddress().String()),
+		widget.NewLabel(i.node.OverlayEthAddress().String()),
 		addrCopyButton,
 	)
 	return container.NewVBox(addrHeader, addr)
 }
 
-func (i *index) balanceContent() *fyne.Container {
-	chequebookBalance, err := i.bl.ChequebookBalance()
-	if err != nil {
-		i.logger.Log(fmt.Sprintf("Cannot get chequebook balance: %s", err.Error()))
-		return container.NewHBox(widget.NewLabel("Cannot get chequebook balance"))
-	}
-
-	balanceContent := container.NewHBox(widget.NewLabel(
-		fmt.Sprintf("Chequebook balance: %s %s", chequebookBalance.String(), SwarmTokenSymbol)))
-
-	// auto reload
-	go func() {
-		for {
-			time.Sleep(time.Second * 60)
-			chequebookBalance, err := i.bl.ChequebookBalance()
-			if err != nil {
-				i.logger.Log(fmt.Sprintf("Cannot get chequebook balance: %s", err.Error()))
-			} else {
-				balanceContent = container.NewHBox(widget.NewLabel(
-					fmt.Sprintf("Chequebook balance: %s %s", chequebookBalance.String(), SwarmTokenSymbol)))
-			}
-		}
-	}()
-
-	return balanceContent
-}
-
-func (i *index) stampsContent(batchRadio *widget.RadioGroup) *fyne.Container {
+// stampsContent lists the usable batches in the radio group as the stamps binding changes, keeping the selection.
+func (i *index) stampsContent(batchRadio *widget.RadioGroup, stamps binding.StringList) *fyne.Container {
 	stampsHeader := container.NewHBox(widget.NewLabel("Postage stamps:"))
-	stamps := i.bl.GetUsableBatches()
-
-	if len(stamps) != 0 {
+	stamps.AddListener(binding.NewDataListener(func() {
+		options, err := stamps.Get()
+		if err != nil {
+			return
+		}
+		batchRadio.Options = options
+		batchRadio.Selected = ""
 		selectedStamp := i.getPreferenceString(selectedStampPrefKey)
-		for _, v := range stamps {
-			batchRadio.Append(shortenHashOrAddress(hex.EncodeToString(v.ID())))
+		for _, v := range options {
+			if v == selectedStamp {
+				batchRadio.Selected = selectedStamp
+			}
 		}
-
-		batchRadio.SetSelected(selectedStamp)
-	}
+		batchRadio.Refresh()
+	}))
 	return container.NewVBox(stampsHeader, batchRadio)
 }
 
@@ -120,7 +124,7 @@ func (i *index) batchRadio() *widget.RadioGroup {
 			i.setPreference(batchPrefKey, "")
 			return
 		}
-		batches := i.bl.GetUsableBatches()
+		batches := i.node.GetUsableBatches()
 		for _, v := range batches {
 			stamp := hex.EncodeToString(v.ID())
 			if s[0:6] == stamp[0:6] {
@@ -131,15 +135,24 @@ func (i *index) batchRadio() *widget.RadioGroup {
 	})
 }
 
-func (i *index) buyBatchButton(batchRadio *widget.RadioGroup) *widget.Button {
+// buyBatchButton opens the batch purchase form. onBought is called once a batch is bought.
+func (i *index) buyBatchButton(onBought func()) *widget.Button {
 	return widget.NewButton("Buy a postage batch", func() {
-		child := i.app.NewWindow("Buying a postage batch")
-		depthStr := defaultDepth
-		amountStr := defaultAmount
-		isImmutable := defaultImmutable
+		child, ctx := i.newChildWindow("Buying a postage batch")
+		calc := &batchCalculator{blockTime: i.nodeConfig.network.BlockTime}
 		label := ""
+		isImmutable := defaultImmutable
 		content := container.NewStack()
-		buyBatchContent := i.buyBatchForm(&depthStr, &amountStr, &label, &isImmutable)
+		buyButton := widget.NewButton("Buy", nil)
+		buyButton.Importance = widget.HighImportance
+		buyButton.Disable()
+		buyBatchContent := i.buyBatchForm(calc, &label, &isImmutable, func(err error) {
+			if err != nil {
+				buyButton.Disable()
+			} else {
+				buyButton.Enable()
+			}
+		})
 		size := child.Canvas().Content().MinSize()
 		if size.Width < 250 {
 			size.Width = 250
@@ -149,56 +162,147 @@ func (i *index) buyBatchButton(batchRadio *widget.RadioGroup) *widget.Button {
 		}
 		child.Resize(size)
 
-		buyButton := widget.NewButton("Buy", func() {
-			amount, ok := big.NewInt(0).SetString(amountStr, 10)
-			if !ok {
-				i.showError(fmt.Errorf("invalid amountStr"))
-				return
-			}
-			depth, err := strconv.ParseUint(depthStr, 10, 8)
+		buyButton.OnTapped = func() {
+			depth, amount, cost, err := calc.batch()
 			if err != nil {
-				i.showError(fmt.Errorf("invalid depthStr %s", err.Error()))
-				return
-			}
-			child.Close()
-			i.showProgressWithMessage(fmt.Sprintf("Buying a postage batch\ndepth: %s, amount: %s, label: \"%s\", immutable: %t", depthStr, amountStr, label, isImmutable))
-			hash, id, err := i.bl.BuyStamp(amount, depth, label, isImmutable)
-			if err != nil {
-				i.hideProgress()
 				i.showError(err)
 				return
 			}
-			i.logger.Log(fmt.Sprintf("Batch created: %s", hash.String()))
-			i.hideProgress()
-			batchRadio.Append(shortenHashOrAddress(hex.EncodeToString(id)))
-		})
-		buyButton.Importance = widget.HighImportance
+			i.authenticate(child, "Buy a postage batch", func() {
+				child.Close()
+				go func() {
+					i.showProgressWithMessage(fmt.Sprintf("Buying a postage batch\ndepth: %d, amount: %s, cost: %s, label: \"%s\", immutable: %t", depth, amount.String(), formatBZZ(cost), label, isImmutable))
+					hash, id, err := i.node.BuyStamp(amount, uint64(depth), label, isImmutable)
+					i.hideProgress()
+					if err != nil {
+						i.showError(err)
+						return
+					}
+					i.logger.Log(fmt.Sprintf("Batch created: %s, transaction: %s", hex.EncodeToString(id), hash.String()))
+					onBought()
+				}()
+			})
+		}
 		content.Objects = []fyne.CanvasObject{container.NewBorder(buyBatchContent, container.NewVBox(buyButton), nil, nil)}
 		child.SetContent(content)
 		child.Show()
+
+		go calc.load(ctx, i.node)
 	})
 }
 
-func (i *index) buyBatchForm(depthStr, amountStr, label *string, isImmutable *bool) fyne.CanvasObject {
-	depthBind := binding.BindString(depthStr)
-	amountBind := binding.BindString(amountStr)
-	labelBind := binding.BindString(label)
-	immutableBind := binding.BindBool(isImmutable)
+// batchCalculator turns a capacity and a duration into the depth and amount of a batch
+// using the current storage price, and checks the cost against the wallet balance.
+type batchCalculator struct {
+	blockTime time.Duration
+	capacity  int64
+	encrypted bool
+	duration  time.Duration
+	state     *node.ChainState
+	balance   *big.Int
+	loadErr   error
+	onLoad    func()
+}
 
-	amountEntry := widget.NewEntryWithData(amountBind)
-	amountEntry.OnChanged = func(s string) {
-		err := amountBind.Set(s)
+// load fetches the current price and the xBZZ balance of the wallet.
+func (c *batchCalculator) load(ctx context.Context, n node.Node) {
+	state, err := n.ChainState(ctx)
+	if err != nil {
+		err = fmt.Errorf("failed to get the current price: %w", err)
+	}
+	var wallet node.Wallet
+	if err == nil {
+		wallet, err = n.Wallet(ctx)
 		if err != nil {
-			i.logger.Log(fmt.Sprintf("failed to bind amount: %s", err.Error()))
+			err = fmt.Errorf("failed to get the wallet balance: %w", err)
 		}
 	}
-	depthEntry := widget.NewEntryWithData(depthBind)
-	depthEntry.OnChanged = func(s string) {
-		err := depthBind.Set(s)
-		if err != nil {
-			i.logger.Log(fmt.Sprintf("failed to bind depth: %s", err.Error()))
+	fyne.Do(func() {
+		c.loadErr = err
+		if err == nil {
+			c.state = &state
+			c.balance = wallet.BZZ
+		}
+		if c.onLoad != nil {
+			c.onLoad()
+		}
+	})
+}
+
+// batch returns the depth, the amount per chunk and the cost of the batch.
+func (c *batchCalculator) batch() (uint8, *big.Int, *big.Int, error) {
+	if c.loadErr != nil {
+		return 0, nil, nil, c.loadErr
+	}
+	if c.state == nil {
+		return 0, nil, nil, fmt.Errorf("fetching the current price")
+	}
+	if c.capacity <= 0 {
+		return 0, nil, nil, fmt.Errorf("invalid capacity")
+	}
+	if c.duration <= 0 {
+		return 0, nil, nil, fmt.Errorf("invalid duration")
+	}
+	if c.state.CurrentPrice.Sign() <= 0 {
+		return 0, nil, nil, fmt.Errorf("the current price is not known yet")
+	}
+
+	depth, err := node.DepthForCapacity(c.capacity, c.encrypted)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	amount := c.state.Amount(c.duration, c.blockTime)
+	cost := node.BatchCost(amount, depth)
+	if cost.Cmp(c.balance) > 0 {
+		return depth, amount, cost, fmt.Errorf("the batch costs %s but the wallet only has %s", formatBZZ(cost), formatBZZ(c.balance))
+	}
+	return depth, amount, cost, nil
+}
+
+func (i *index) buyBatchForm(calc *batchCalculator, label *string, isImmutable *bool, onChanged func(error)) fyne.CanvasObject {
+	labelBind := binding.BindString(label)
+	immutableBind := binding.BindBool(isImmutable)
+
+	summary := widget.NewLabel("Fetching the current price...")
+	summary.Wrapping = fyne.TextWrapWord
+	capacityEntry := widget.NewEntry()
+	capacityEntry.SetText(defaultCapacity)
+	capacityUnit := widget.NewSelect([]string{unitMB, unitGB}, nil)
+	capacityUnit.SetSelected(unitGB)
+	durationEntry := widget.NewEntry()
+	durationEntry.SetText(defaultDuration)
+
+	update := func() {
+		calc.capacity = 0
+		if v, err := strconv.ParseFloat(capacityEntry.Text, 64); err == nil && v > 0 {
+			calc.capacity = int64(v * float64(capacityUnits[capacityUnit.Selected]))
+		}
+		calc.duration = 0
+		if v, err := strconv.ParseFloat(durationEntry.Text, 64); err == nil && v > 0 {
+			calc.duration = time.Duration(v * float64(24*time.Hour))
 		}
+
+		depth, amount, cost, err := calc.batch()
+		switch {
+		case amount == nil && err != nil:
+			summary.SetText(err.Error())
+		case err != nil:
+			summary.SetText(fmt.Sprintf("Depth %d, amount %s PLUR per chunk\n%s", depth, amount.String(), err.Error()))
+		default:
+			summary.SetText(fmt.Sprintf("Depth %d, amount %s PLUR per chunk\nEffective capacity: %s\nCost: %s, wallet balance: %s",
+				depth, amount.String(), formatBytes(node.EffectiveVolume(depth, calc.encrypted)), formatBZZ(cost), formatBZZ(calc.balance)))
+		}
+		onChanged(err)
 	}
+	calc.onLoad = update
+	capacityEntry.OnChanged = func(string) { update() }
+	capacityUnit.OnChanged = func(string) { update() }
+	durationEntry.OnChanged = func(string) { update() }
+	encryptedCheck := widget.NewCheck("Encrypted uploads", func(b bool) {
+		calc.encrypted = b
+		update()
+	})
+
 	labelEntry := widget.NewEntryWithData(labelBind)
 	labelEntry.OnChanged = func(s string) {
 		err := labelBind.Set(s)
@@ -217,12 +321,16 @@ func (i *index) buyBatchForm(depthStr, amountStr, label *string, isImmutable *bo
 
 	optionsForm := widget.NewForm()
 	optionsForm.Append(
-		"Depth",
-		container.NewStack(depthEntry),
+		"Capacity",
+		container.NewBorder(nil, nil, nil, capacityUnit, capacityEntry),
+	)
+	optionsForm.Append(
+		"",
+		encryptedCheck,
 	)
 	optionsForm.Append(
-		"Amount",
-		container.NewStack(amountEntry),
+		"Duration",
+		container.NewBorder(nil, nil, nil, widget.NewLabel("days"), durationEntry),
 	)
 	optionsForm.Append(
 		"Label",
@@ -232,6 +340,10 @@ func (i *index) buyBatchForm(depthStr, amountStr, label *string, isImmutable *bo
 		"",
 		immutableCheck,
 	)
+	optionsForm.Append(
+		"",
+		summary,
+	)
 
 	return container.NewStack(optionsForm)
 }
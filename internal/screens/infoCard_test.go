@@ -0,0 +1,95 @@
+package screens
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Solar-Punk-Ltd/swarm-mobile/internal/node"
+	"github.com/Solar-Punk-Ltd/swarm-mobile/internal/node/nodetest"
+)
+
+func testBatch(id string) node.Batch {
+	return node.Batch{
+		ID:          id,
+		Depth:       node.MinBatchDepth + 3,
+		BucketDepth: node.MinBatchDepth - 1,
+		Amount:      big.NewInt(1_000_000),
+		Immutable:   true,
+		Usable:      true,
+		TTL:         30 * 24 * time.Hour,
+	}
+}
+
+// loadCalculator fetches the price and the balance into calc, and waits for them to be set on the UI goroutine.
+func loadCalculator(t *testing.T, calc *batchCalculator, n node.Node) {
+	t.Helper()
+	loaded := make(chan struct{})
+	calc.onLoad = func() { close(loaded) }
+	calc.load(context.Background(), n)
+	select {
+	case <-loaded:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the calculator did not load")
+	}
+}
+
+func TestBatchCalculator(t *testing.T) {
+	n := nodetest.New()
+	n.Chain.CurrentPrice = big.NewInt(24000)
+	n.Account.BZZ = big.NewInt(100 * plurPerBZZ)
+	calc := &batchCalculator{blockTime: node.Mainnet.BlockTime, capacity: 1 << 30, duration: 30 * 24 * time.Hour}
+
+	if _, _, _, err := calc.batch(); err == nil {
+		t.Fatal("expected an error before the price is loaded")
+	}
+	loadCalculator(t, calc, n)
+
+	depth, amount, cost, err := calc.batch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if depth != 21 {
+		t.Errorf("got depth %d, want 21", depth)
+	}
+	blocks := int64(30 * 24 * time.Hour / node.Mainnet.BlockTime)
+	if want := big.NewInt(24000 * blocks); amount.Cmp(want) != 0 {
+		t.Errorf("got amount %s, want %s", amount, want)
+	}
+	if want := new(big.Int).Lsh(amount, 21); cost.Cmp(want) != 0 {
+		t.Errorf("got cost %s, want %s", cost, want)
+	}
+
+	calc.encrypted = true
+	calc.capacity = 2_590_000_000
+	if depth, _, _, _ := calc.batch(); depth != 22 {
+		t.Errorf("got depth %d for encrypted uploads, want 22", depth)
+	}
+}
+
+func TestBatchCalculatorErrors(t *testing.T) {
+	n := nodetest.New()
+	n.Chain.CurrentPrice = big.NewInt(24000)
+	n.Account.BZZ = big.NewInt(1)
+	calc := &batchCalculator{blockTime: node.Mainnet.BlockTime, capacity: 1 << 30, duration: 30 * 24 * time.Hour}
+	loadCalculator(t, calc, n)
+	if _, _, _, err := calc.batch(); err == nil || !strings.Contains(err.Error(), "wallet only has") {
+		t.Errorf("got error %v, want an insufficient balance error", err)
+	}
+
+	n.ChainStateErr = errors.New("rpc down")
+	loadCalculator(t, calc, n)
+	if _, _, _, err := calc.batch(); !errors.Is(err, n.ChainStateErr) {
+		t.Errorf("got error %v, want %v", err, n.ChainStateErr)
+	}
+
+	n.ChainStateErr = nil
+	loadCalculator(t, calc, n)
+	calc.duration = 0
+	if _, _, _, err := calc.batch(); err == nil {
+		t.Error("expected an error for a zero duration")
+	}
+}
@@ -0,0 +1,112 @@
+package screens
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"fyne.io/fyne/v2/data/binding"
+)
+
+const (
+	infoRefreshInterval = 10 * time.Second
+	infoMaxBackoff      = 5 * time.Minute
+	infoRequestTimeout  = 15 * time.Second
+)
+
+// infoRefresher is the single refresh loop of the Info card. It writes the values into
+// bindings the widgets are bound to, and backs off while the node is unreachable.
+type infoRefresher struct {
+	*index
+	lightMode bool
+	peers     binding.String
+	balance   binding.String
+	stamps    binding.StringList
+	status    binding.String
+	refreshC  chan struct{}
+}
+
+func (i *index) newInfoRefresher(lightMode bool) *infoRefresher {
+	return &infoRefresher{
+		index:     i,
+		lightMode: lightMode,
+		peers:     binding.NewString(),
+		balance:   binding.NewString(),
+		stamps:    binding.NewStringList(),
+		status:    binding.NewString(),
+		refreshC:  make(chan struct{}, 1),
+	}
+}
+
+// refresh asks the loop to refresh now, without waiting for the next tick.
+func (r *infoRefresher) refresh() {
+	select {
+	case r.refreshC <- struct{}{}:
+	default:
+	}
+}
+
+// run refreshes the bindings until ctx is done. The interval doubles after every failure, up to infoMaxBackoff.
+func (r *infoRefresher) run(ctx context.Context) {
+	delay := infoRefreshInterval
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-timer.C:
+		case <-r.refreshC:
+			timer.Stop()
+		case <-ctx.Done():
+			return
+		}
+
+		err := r.update(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			delay = min(delay*2, infoMaxBackoff)
+			r.logger.Log(fmt.Sprintf("Cannot refresh node info: %s", err.Error()))
+			r.set(r.status, fmt.Sprintf("Node unreachable, retrying in %s", delay))
+		} else {
+			delay = infoRefreshInterval
+			r.set(r.status, fmt.Sprintf("Updated at %s", time.Now().Format("15:04:05")))
+		}
+		timer.Reset(delay)
+	}
+}
+
+func (r *infoRefresher) update(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, infoRequestTimeout)
+	defer cancel()
+	if err := r.node.Health(ctx); err != nil {
+		return err
+	}
+	r.set(r.peers, fmt.Sprintf("Connected with %d peers", r.node.ConnectedPeerCount()))
+	if !r.lightMode {
+		return nil
+	}
+
+	stamps := []string{}
+	for _, v := range r.node.GetUsableBatches() {
+		stamps = append(stamps, shortenHashOrAddress(hex.EncodeToString(v.ID())))
+	}
+	if err := r.stamps.Set(stamps); err != nil {
+		r.logger.Log(fmt.Sprintf("failed to bind stamps: %s", err.Error()))
+	}
+
+	chequebookBalance, err := r.node.ChequebookBalance()
+	if err != nil {
+		r.set(r.balance, "Cannot get chequebook balance")
+		return fmt.Errorf("chequebook balance: %w", err)
+	}
+	r.set(r.balance, fmt.Sprintf("Chequebook balance: %s", formatBZZ(chequebookBalance)))
+	return nil
+}
+
+func (r *infoRefresher) set(b binding.String, s string) {
+	if err := b.Set(s); err != nil {
+		r.logger.Log(fmt.Sprintf("failed to bind info: %s", err.Error()))
+	}
+}
@@ -0,0 +1,80 @@
+package screens
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"fyne.io/fyne/v2/data/binding"
+
+	"github.com/Solar-Punk-Ltd/swarm-mobile/internal/node"
+	"github.com/Solar-Punk-Ltd/swarm-mobile/internal/node/nodetest"
+)
+
+func bindingValue(t *testing.T, b binding.String) string {
+	t.Helper()
+	v, err := b.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestInfoRefresherUpdate(t *testing.T) {
+	n := nodetest.New()
+	if err := n.Start(&node.Options{}); err != nil {
+		t.Fatal(err)
+	}
+	n.Peers = 4
+	n.Chequebook = big.NewInt(2 * plurPerBZZ)
+	batchID := "a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2"
+	n.Stamps = append(n.Stamps, testBatch(batchID))
+	r := newTestIndex(t, n).newInfoRefresher(true)
+
+	if err := r.update(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := bindingValue(t, r.peers); got != "Connected with 4 peers" {
+		t.Errorf("got peers %q", got)
+	}
+	if got, want := bindingValue(t, r.balance), fmt.Sprintf("Chequebook balance: %s", formatBZZ(n.Chequebook)); got != want {
+		t.Errorf("got balance %q, want %q", got, want)
+	}
+	stamps, err := r.stamps.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stamps) != 1 || stamps[0] != shortenHashOrAddress(batchID) {
+		t.Errorf("got stamps %v", stamps)
+	}
+}
+
+func TestInfoRefresherUpdateErrors(t *testing.T) {
+	n := nodetest.New()
+	if err := n.Start(&node.Options{}); err != nil {
+		t.Fatal(err)
+	}
+	r := newTestIndex(t, n).newInfoRefresher(true)
+
+	n.HealthErr = errors.New("unreachable")
+	if err := r.update(context.Background()); !errors.Is(err, n.HealthErr) {
+		t.Fatalf("got error %v, want %v", err, n.HealthErr)
+	}
+
+	n.HealthErr = nil
+	n.ChequebookErr = errors.New("no chequebook")
+	if err := r.update(context.Background()); !errors.Is(err, n.ChequebookErr) {
+		t.Fatalf("got error %v, want %v", err, n.ChequebookErr)
+	}
+	if got := bindingValue(t, r.balance); got != "Cannot get chequebook balance" {
+		t.Errorf("got balance %q", got)
+	}
+
+	// the chequebook is not queried in ultra-light mode
+	r.lightMode = false
+	if err := r.update(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,203 @@
+package screens
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/Solar-Punk-Ltd/swarm-mobile/internal/node"
+)
+
+const keyArchiveExtension = ".swarmkeys"
+
+// exportKeysButton saves the keystore as an archive encrypted with a new password to a location the user picks.
+func (i *index) exportKeysButton() *widget.Button {
+	button := widget.NewButton("Export keys", func() {
+		passwordEntry := widget.NewPasswordEntry()
+		confirmEntry := widget.NewPasswordEntry()
+		confirmEntry.Validator = func(s string) error {
+			if s != passwordEntry.Text {
+				return fmt.Errorf("passwords do not match")
+			}
+			return nil
+		}
+		items := []*widget.FormItem{
+			{Text: "Password", Widget: passwordEntry, HintText: "Protects the archive, needed to import it"},
+			{Text: "Confirm", Widget: confirmEntry},
+		}
+		d := dialog.NewForm("Export keys", "Next", "Cancel", items, func(ok bool) {
+			if !ok {
+				return
+			}
+			if passwordEntry.Text == "" {
+				i.showError(fmt.Errorf("password cannot be blank"))
+				return
+			}
+			i.authenticate(i.Window, "Export keys", func() {
+				i.saveKeyArchive(passwordEntry.Text)
+			})
+		}, i.Window)
+		parentSize := i.Window.Canvas().Size()
+		d.Resize(fyne.NewSize(parentSize.Width*90/100, 0))
+		d.Show()
+	})
+	if i.nodeConfig.isKeyStoreMem {
+		button.Disable()
+	}
+	return button
+}
+
+func (i *index) saveKeyArchive(password string) {
+	saveFile := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil {
+			i.showError(err)
+			return
+		}
+		if writer == nil {
+			return
+		}
+		go func() {
+			defer writer.Close()
+			i.showProgressWithMessage("Exporting keys")
+			archive, err := node.ExportKeys(i.nodeConfig.path, password)
+			if err == nil {
+				_, err = writer.Write(archive)
+			}
+			i.hideProgress()
+			if err != nil {
+				i.showError(fmt.Errorf("failed to export keys: %w", err))
+				return
+			}
+			i.logger.Log(fmt.Sprintf("Exported keys to %s", writer.URI().String()))
+			fyne.Do(func() {
+				dialog.ShowInformation("Export keys", fmt.Sprintf("Keys saved to %s.\nThe node password is still needed to use them.", writer.URI().Name()), i.Window)
+			})
+		}()
+	}, i.Window)
+	saveFile.SetFileName(fmt.Sprintf("swarm-%s%s", i.node.OverlayEthAddress().Hex(), keyArchiveExtension))
+	saveFile.Show()
+}
+
+// importKeysButton lets the user restore a node identity from a key archive on first launch.
+// onImported is called with the node password the keys are encrypted with.
+func (i *index) importKeysButton(onImported func(password string)) *widget.Button {
+	button := widget.NewButton("Import keys from backup", func() {
+		fd := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil {
+				i.showError(err)
+				return
+			}
+			if reader == nil {
+				return
+			}
+			defer reader.Close()
+			archive, err := io.ReadAll(reader)
+			if err != nil {
+				i.showError(fmt.Errorf("failed to read key archive: %w", err))
+				return
+			}
+			i.showImportKeys(archive, onImported)
+		}, i.Window)
+		fd.Show()
+	})
+	if i.nodeConfig.isKeyStoreMem {
+		button.Hide()
+	}
+	return button
+}
+
+func (i *index) showImportKeys(archive []byte, onImported func(password string)) {
+	archivePasswordEntry := widget.NewPasswordEntry()
+	nodePasswordEntry := widget.NewPasswordEntry()
+	items := []*widget.FormItem{
+		{Text: "Archive password", Widget: archivePasswordEntry, HintText: "Password chosen at the export"},
+		{Text: "Node password", Widget: nodePasswordEntry, HintText: "Password the node was initialised with"},
+	}
+	d := dialog.NewForm("Import keys", "Import", "Cancel", items, func(ok bool) {
+		if !ok {
+			return
+		}
+		if nodePasswordEntry.Text == "" {
+			i.showError(fmt.Errorf("password cannot be blank"))
+			return
+		}
+		importKeys := func() {
+			go i.importKeys(archive, archivePasswordEntry.Text, nodePasswordEntry.Text, onImported)
+		}
+		exists, err := node.KeysExist(i.nodeConfig.path)
+		if err != nil {
+			i.showError(err)
+			return
+		}
+		if !exists {
+			importKeys()
+			return
+		}
+		dialog.ShowConfirm("Replace keys", "This device already has node keys, they will be replaced by the imported ones. Continue?", func(ok bool) {
+			if ok {
+				importKeys()
+			}
+		}, i.Window)
+	}, i.Window)
+	parentSize := i.Window.Canvas().Size()
+	d.Resize(fyne.NewSize(parentSize.Width*90/100, 0))
+	d.Show()
+}
+
+func (i *index) importKeys(archive []byte, archivePassword, nodePassword string, onImported func(password string)) {
+	i.showProgressWithMessage("Importing keys")
+	address, err := node.ImportKeys(i.nodeConfig.path, archive, archivePassword, nodePassword)
+	i.hideProgress()
+	if errors.Is(err, node.ErrNodeStateExists) {
+		i.confirmResetNodeState(func() {
+			i.importKeys(archive, archivePassword, nodePassword, onImported)
+		})
+		return
+	}
+	if errors.Is(err, node.ErrInvalidPassword) {
+		err = fmt.Errorf("the node password does not match the imported keys")
+	}
+	if err != nil {
+		i.showError(fmt.Errorf("failed to import keys: %w", err))
+		return
+	}
+	i.logger.Log(fmt.Sprintf("Imported keys of %s", address.Hex()))
+	i.setPreference(overlayAddrPrefKey, address.Hex())
+	fyne.Do(func() {
+		i.showImportedAddress(address)
+		onImported(nodePassword)
+	})
+}
+
+// confirmResetNodeState asks to delete the data of the previous node, which cannot start with another identity,
+// then runs retry in the background.
+func (i *index) confirmResetNodeState(retry func()) {
+	message := "This device holds the data of another node: its peers, cached chunks and postage stamp usage.\n" +
+		"The node cannot start with the imported keys while this data is kept.\n" +
+		"Export the current keys first to keep the previous node. Delete the data and continue?"
+	fyne.Do(func() {
+		dialog.ShowConfirm("Replace node", message, func(ok bool) {
+			if !ok {
+				return
+			}
+			go func() {
+				if err := node.ResetNodeState(i.nodeConfig.path); err != nil {
+					i.showError(fmt.Errorf("failed to delete the node data: %w", err))
+					return
+				}
+				i.logger.Log("Deleted the data of the previous node")
+				retry()
+			}()
+		}, i.Window)
+	})
+}
+
+func (i *index) showImportedAddress(address common.Address) {
+	dialog.ShowCustom("Keys imported", "Ok", i.copyDialog(fmt.Sprintf("Node address: %s", shortenHashOrAddress(address.Hex())), address.Hex()), i.Window)
+}
@@ -0,0 +1,125 @@
+package screens
+
+import (
+	"errors"
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/Solar-Punk-Ltd/swarm-mobile/internal/node"
+)
+
+// removeStoredPassword deletes the node password that older versions saved in plain text in the preferences.
+func (i *index) removeStoredPassword() {
+	if i.getPreferenceString(passwordPrefKey) == "" {
+		return
+	}
+	i.app.Preferences().RemoveValue(i.profile.prefKey(passwordPrefKey))
+	i.logger.Log("Removed the plain text password from the preferences")
+}
+
+// keysExist reports whether the node keys are in the app datadir, so the node was set up before.
+func (i *index) keysExist() bool {
+	exists, err := node.KeysExist(i.nodeConfig.path)
+	if err != nil {
+		i.logger.Log(fmt.Sprintf("failed to check the keystore: %s", err.Error()))
+	}
+	return exists
+}
+
+// showUnlockView asks for the node password on launch. The password is checked against the keystore
+// and kept only in memory.
+func (i *index) showUnlockView() fyne.CanvasObject {
+	i.intro.SetText("Unlock your swarm node")
+	content := container.NewStack()
+	passwordEntry := widget.NewPasswordEntry()
+	passwordEntry.SetPlaceHolder("Password")
+	unlock := func() {
+		password := passwordEntry.Text
+		if password == "" {
+			i.showError(fmt.Errorf("password cannot be blank"))
+			return
+		}
+		go func() {
+			i.showProgressWithMessage("Unlocking")
+			err := node.VerifyPassword(i.nodeConfig.path, password)
+			i.hideProgress()
+			if errors.Is(err, node.ErrInvalidPassword) {
+				err = fmt.Errorf("wrong password")
+			}
+			if err != nil {
+				i.showError(err)
+				return
+			}
+			fyne.Do(func() {
+				i.nodeConfig.password = password
+				content.Objects = []fyne.CanvasObject{i.showStartView(false)}
+				content.Refresh()
+			})
+		}()
+	}
+	passwordEntry.OnSubmitted = func(string) { unlock() }
+	unlockButton := widget.NewButton("Unlock", unlock)
+	unlockButton.Importance = widget.HighImportance
+	content.Objects = []fyne.CanvasObject{container.NewBorder(passwordEntry, container.NewVBox(unlockButton, i.profilesButton()), nil, nil)}
+	i.content = content
+	return content
+}
+
+// changePasswordButton re-encrypts the keystore with a new node password.
+func (i *index) changePasswordButton() *widget.Button {
+	button := widget.NewButton("Change password", func() {
+		currentEntry := widget.NewPasswordEntry()
+		newEntry := widget.NewPasswordEntry()
+		confirmEntry := widget.NewPasswordEntry()
+		confirmEntry.Validator = func(s string) error {
+			if s != newEntry.Text {
+				return fmt.Errorf("passwords do not match")
+			}
+			return nil
+		}
+		items := []*widget.FormItem{
+			{Text: "Current", Widget: currentEntry},
+			{Text: "New", Widget: newEntry},
+			{Text: "Confirm", Widget: confirmEntry},
+		}
+		d := dialog.NewForm("Change password", "Change", "Cancel", items, func(ok bool) {
+			if !ok {
+				return
+			}
+			if newEntry.Text == "" {
+				i.showError(fmt.Errorf("password cannot be blank"))
+				return
+			}
+			go i.changePassword(currentEntry.Text, newEntry.Text)
+		}, i.Window)
+		parentSize := i.Window.Canvas().Size()
+		d.Resize(fyne.NewSize(parentSize.Width*90/100, 0))
+		d.Show()
+	})
+	if i.nodeConfig.isKeyStoreMem {
+		button.Hide()
+	}
+	return button
+}
+
+func (i *index) changePassword(current, password string) {
+	i.showProgressWithMessage("Changing the password")
+	err := node.ChangePassword(i.nodeConfig.path, current, password)
+	i.hideProgress()
+	if errors.Is(err, node.ErrInvalidPassword) {
+		err = fmt.Errorf("the current password is wrong")
+	}
+	if err != nil {
+		i.showError(fmt.Errorf("failed to change the password: %w", err))
+		return
+	}
+	i.nodeConfig.password = password
+	i.logger.Log("Node password changed")
+	fyne.Do(func() {
+		dialog.ShowInformation("Change password", "The keys are encrypted with the new password.\nUse it to unlock the node from now on.", i.Window)
+	})
+}
@@ -0,0 +1,190 @@
+package screens
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/Solar-Punk-Ltd/swarm-mobile/internal/node"
+)
+
+// peersBrowser shows the connected peers and the kademlia bins of the node. The underlay addresses are
+// the ones of the node itself, the node does not expose the underlay addresses of its peers.
+type peersBrowser struct {
+	*index
+	window         fyne.Window
+	ctx            context.Context
+	mu             sync.Mutex
+	peers          []node.Peer
+	list           *widget.List
+	summaryLabel   *widget.Label
+	histogramLabel *widget.Label
+	underlayLabel  *widget.Label
+	emptyLabel     *widget.Label
+}
+
+func (i *index) listPeersButton(minSize fyne.Size) *widget.Button {
+	return widget.NewButton("Peers", func() {
+		child, ctx := i.newChildWindow("Peers")
+		b := &peersBrowser{index: i, window: child, ctx: ctx}
+		child.SetContent(b.content())
+		go b.reload()
+
+		size := child.Canvas().Content().MinSize()
+		if size.Width < minSize.Width {
+			size.Width = minSize.Width
+		}
+		if size.Height < minSize.Height {
+			size.Height = minSize.Height
+		}
+		child.Resize(size)
+		child.Show()
+	})
+}
+
+func (b *peersBrowser) content() fyne.CanvasObject {
+	b.list = widget.NewList(
+		func() int {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			return len(b.peers)
+		},
+		func() fyne.CanvasObject {
+			address := widget.NewLabel("")
+			address.TextStyle.Bold = true
+			return container.NewVBox(address, widget.NewLabel(""))
+		},
+		func(id widget.ListItemID, o fyne.CanvasObject) {
+			peer := b.peer(id)
+			labels := o.(*fyne.Container).Objects
+			labels[0].(*widget.Label).SetText(shortenHashOrAddress(peer.Address))
+			labels[1].(*widget.Label).SetText(peerSummary(peer))
+		},
+	)
+	b.list.OnSelected = func(id widget.ListItemID) {
+		b.list.Unselect(id)
+		b.showDetails(b.peer(id))
+	}
+
+	b.summaryLabel = widget.NewLabel("Loading...")
+	b.histogramLabel = widget.NewLabel("")
+	b.histogramLabel.TextStyle.Monospace = true
+	b.underlayLabel = widget.NewLabel("")
+	b.underlayLabel.Wrapping = fyne.TextWrapBreak
+	b.emptyLabel = widget.NewLabel("No connected peers")
+	b.emptyLabel.Hide()
+	refreshButton := widget.NewButtonWithIcon("Refresh", theme.ViewRefreshIcon(), func() {
+		go b.reload()
+	})
+
+	header := container.NewVBox(b.summaryLabel, widget.NewAccordion(
+		widget.NewAccordionItem("Bins", b.histogramLabel),
+		widget.NewAccordionItem("This node's underlay addresses", b.underlayLabel),
+	))
+	return container.NewBorder(header, container.NewVBox(b.emptyLabel, refreshButton), nil, nil, b.list)
+}
+
+func (b *peersBrowser) peer(id widget.ListItemID) node.Peer {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.peers[id]
+}
+
+func (b *peersBrowser) reload() {
+	t, err := b.node.Topology(b.ctx)
+	if err != nil {
+		if b.ctx.Err() == nil {
+			b.showErrorIn(b.window, fmt.Errorf("failed to get the topology: %w", err))
+		}
+		return
+	}
+
+	b.mu.Lock()
+	b.peers = t.Peers
+	b.mu.Unlock()
+	summary := fmt.Sprintf("Overlay: %s\nDepth: %d, connected %d of %d known\nReachability: %s, network: %s",
+		shortenHashOrAddress(t.Overlay), t.Depth, t.Connected, t.Population, t.Reachability, t.NetworkAvailability)
+	histogram := binHistogram(t.Bins, t.Depth)
+	fyne.Do(func() {
+		b.summaryLabel.SetText(summary)
+		b.histogramLabel.SetText(histogram)
+		underlay := strings.Join(t.Underlay, "\n")
+		if underlay == "" {
+			underlay = "None"
+		}
+		b.underlayLabel.SetText(underlay)
+		if len(t.Peers) == 0 {
+			b.emptyLabel.Show()
+		} else {
+			b.emptyLabel.Hide()
+		}
+		b.list.Refresh()
+	})
+}
+
+// binHistogram draws a bar of the connected peers in every bin, marking the bins at or above the depth.
+func binHistogram(bins []node.Bin, depth uint8) string {
+	var sb strings.Builder
+	for po, bin := range bins {
+		marker := " "
+		if po >= int(depth) {
+			marker = "*"
+		}
+		fmt.Fprintf(&sb, "%2d%s %-10s %d/%d\n", po, marker, strings.Repeat("#", min(bin.Connected, 10)), bin.Connected, bin.Population)
+	}
+	sb.WriteString("* neighbourhood")
+	return sb.String()
+}
+
+func peerSummary(peer node.Peer) string {
+	kind := "light"
+	if peer.FullNode {
+		kind = "full"
+	}
+	latency := "-"
+	if peer.Latency > 0 {
+		latency = peer.Latency.String()
+	}
+	direction := peer.Direction
+	if direction == "" {
+		direction = "unknown"
+	}
+	return fmt.Sprintf("bin %d, %s node, %s, %s", peer.Bin, kind, latency, direction)
+}
+
+func (b *peersBrowser) showDetails(peer node.Peer) {
+	kind := "Light"
+	if peer.FullNode {
+		kind = "Full"
+	}
+	latency := "not measured"
+	if peer.Latency > 0 {
+		latency = peer.Latency.String()
+	}
+	details := widget.NewForm(
+		widget.NewFormItem("Overlay", widget.NewLabel(shortenHashOrAddress(peer.Address))),
+		widget.NewFormItem("Node", widget.NewLabel(kind)),
+		widget.NewFormItem("Bin", widget.NewLabel(fmt.Sprintf("%d", peer.Bin))),
+		widget.NewFormItem("Latency", widget.NewLabel(latency)),
+		widget.NewFormItem("Direction", widget.NewLabel(peer.Direction)),
+		widget.NewFormItem("Reachability", widget.NewLabel(peer.Reachability)),
+		widget.NewFormItem("Healthy", widget.NewLabel(fmt.Sprintf("%t", peer.Healthy))),
+		widget.NewFormItem("Connected for", widget.NewLabel(peer.ConnectedFor.Truncate(time.Second).String())),
+	)
+	copyButton := widget.NewButtonWithIcon("Copy overlay", theme.ContentCopyIcon(), func() {
+		b.window.Clipboard().SetContent(peer.Address)
+	})
+
+	d := dialog.NewCustom(shortenHashOrAddress(peer.Address), "Close", container.NewVBox(details, copyButton), b.window)
+	parentSize := b.window.Canvas().Size()
+	d.Resize(fyne.NewSize(parentSize.Width*90/100, 0))
+	d.Show()
+}
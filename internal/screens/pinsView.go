@@ -0,0 +1,230 @@
+package screens
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/ethersphere/bee/v2/pkg/swarm"
+
+	"github.com/Solar-Punk-Ltd/swarm-mobile/internal/history"
+	"github.com/Solar-Punk-Ltd/swarm-mobile/internal/node"
+)
+
+// pinEntry is a pinned reference with the result of its last integrity check, nil until checked.
+type pinEntry struct {
+	address swarm.Address
+	name    string
+	stat    *node.PinStat
+}
+
+func (e pinEntry) size() string {
+	if e.stat == nil {
+		return "checking..."
+	}
+	return formatBytes(int64(e.stat.Total) * swarm.ChunkSize)
+}
+
+func (e pinEntry) status() string {
+	switch {
+	case e.stat == nil:
+		return "unknown"
+	case e.stat.Missing == 0 && e.stat.Invalid == 0:
+		return "complete"
+	default:
+		return fmt.Sprintf("%d missing, %d invalid chunks", e.stat.Missing, e.stat.Invalid)
+	}
+}
+
+// pinsBrowser lists the pinned references with their local size and integrity.
+type pinsBrowser struct {
+	*index
+	window     fyne.Window
+	ctx        context.Context
+	mu         sync.Mutex
+	entries    []pinEntry
+	list       *widget.List
+	emptyLabel *widget.Label
+}
+
+func (i *index) listPinsButton(minSize fyne.Size) *widget.Button {
+	return widget.NewButton("Pinned content", func() {
+		child, ctx := i.newChildWindow("Pinned content")
+		b := &pinsBrowser{index: i, window: child, ctx: ctx}
+		child.SetContent(b.content())
+		go b.reload()
+
+		size := child.Canvas().Content().MinSize()
+		if size.Width < minSize.Width {
+			size.Width = minSize.Width
+		}
+		if size.Height < minSize.Height {
+			size.Height = minSize.Height
+		}
+		child.Resize(size)
+		child.Show()
+	})
+}
+
+func (b *pinsBrowser) content() fyne.CanvasObject {
+	b.list = widget.NewList(
+		func() int {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			return len(b.entries)
+		},
+		func() fyne.CanvasObject {
+			name := widget.NewLabel("")
+			name.Truncation = fyne.TextTruncateEllipsis
+			name.TextStyle.Bold = true
+			return container.NewVBox(name, widget.NewLabel(""))
+		},
+		func(id widget.ListItemID, o fyne.CanvasObject) {
+			entry := b.entry(id)
+			labels := o.(*fyne.Container).Objects
+			labels[0].(*widget.Label).SetText(entry.name)
+			labels[1].(*widget.Label).SetText(fmt.Sprintf("%s, %s", entry.size(), entry.status()))
+		},
+	)
+	b.list.OnSelected = func(id widget.ListItemID) {
+		b.list.Unselect(id)
+		b.showDetails(b.entry(id))
+	}
+
+	b.emptyLabel = widget.NewLabel("No pinned content")
+	b.emptyLabel.Hide()
+	refreshButton := widget.NewButtonWithIcon("Re-check all", theme.ViewRefreshIcon(), func() {
+		go b.reload()
+	})
+
+	return container.NewBorder(nil, container.NewVBox(b.emptyLabel, refreshButton), nil, nil, b.list)
+}
+
+func (b *pinsBrowser) entry(id widget.ListItemID) pinEntry {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.entries[id]
+}
+
+// reload lists the pins, then checks the integrity of all of them, updating the list as the results arrive.
+func (b *pinsBrowser) reload() {
+	refs, err := b.node.Pins(b.ctx)
+	if err != nil {
+		b.showErrorIn(b.window, err)
+		return
+	}
+
+	entries := make([]pinEntry, 0, len(refs))
+	for _, ref := range refs {
+		entries = append(entries, pinEntry{address: ref, name: b.pinName(ref)})
+	}
+	b.mu.Lock()
+	b.entries = entries
+	b.mu.Unlock()
+	fyne.Do(func() {
+		if len(entries) == 0 {
+			b.emptyLabel.Show()
+		} else {
+			b.emptyLabel.Hide()
+		}
+		b.list.Refresh()
+	})
+	if len(entries) == 0 {
+		return
+	}
+
+	b.check(swarm.ZeroAddress)
+}
+
+// check runs the integrity check of address, or of every pin if address is the zero address.
+func (b *pinsBrowser) check(address swarm.Address) {
+	err := b.node.CheckPins(b.ctx, address, func(stat node.PinStat) {
+		b.mu.Lock()
+		for n := range b.entries {
+			if b.entries[n].address.Equal(stat.Reference) {
+				b.entries[n].stat = &stat
+			}
+		}
+		b.mu.Unlock()
+		fyne.Do(b.list.Refresh)
+	})
+	if err != nil && !errors.Is(err, context.Canceled) {
+		b.showErrorIn(b.window, fmt.Errorf("integrity check failed: %w", err))
+	}
+}
+
+// pinName returns the name of the reference in the upload history, or the shortened reference.
+func (b *pinsBrowser) pinName(ref swarm.Address) string {
+	if b.history != nil {
+		items, err := b.history.Search(ref.String(), history.ByDate, 0, 1)
+		if err == nil && len(items) == 1 && items[0].Reference == ref.String() {
+			return items[0].Name
+		}
+	}
+	return shortenHashOrAddress(ref.String())
+}
+
+func (b *pinsBrowser) showDetails(entry pinEntry) {
+	chunks := "unknown"
+	if entry.stat != nil {
+		chunks = fmt.Sprintf("%d", entry.stat.Total)
+	}
+	details := widget.NewForm(
+		widget.NewFormItem("Reference", widget.NewLabel(shortenHashOrAddress(entry.address.String()))),
+		widget.NewFormItem("Local size", widget.NewLabel(entry.size())),
+		widget.NewFormItem("Chunks", widget.NewLabel(chunks)),
+		widget.NewFormItem("Integrity", widget.NewLabel(entry.status())),
+	)
+
+	var d dialog.Dialog
+	copyButton := widget.NewButtonWithIcon("Copy reference", theme.ContentCopyIcon(), func() {
+		b.window.Clipboard().SetContent(entry.address.String())
+	})
+	checkButton := widget.NewButtonWithIcon("Re-check integrity", theme.ViewRefreshIcon(), func() {
+		d.Hide()
+		go b.check(entry.address)
+	})
+	unpinButton := widget.NewButtonWithIcon("Unpin", theme.DeleteIcon(), func() {
+		dialog.ShowConfirm("Unpin", fmt.Sprintf("Unpin %s?\nThe local copy may be removed from the node.", entry.name), func(ok bool) {
+			if !ok {
+				return
+			}
+			d.Hide()
+			go func() {
+				if err := b.node.Unpin(b.ctx, entry.address); err != nil {
+					b.showErrorIn(b.window, err)
+					return
+				}
+				b.logger.Log(fmt.Sprintf("unpinned %s", entry.address.String()))
+				b.reload()
+			}()
+		}, b.window)
+	})
+	unpinButton.Importance = widget.DangerImportance
+
+	content := container.NewVBox(details, copyButton, checkButton, unpinButton)
+	d = dialog.NewCustom(entry.name, "Close", content, b.window)
+	parentSize := b.window.Canvas().Size()
+	d.Resize(fyne.NewSize(parentSize.Width*90/100, 0))
+	d.Show()
+}
+
+// pin pins the reference in the background, reporting failures in the main window.
+func (i *index) pin(address swarm.Address) {
+	i.logger.Log(fmt.Sprintf("pinning %s", address.String()))
+	if err := i.node.Pin(i.ctx, address); err != nil {
+		if errors.Is(err, context.Canceled) {
+			return
+		}
+		i.showError(fmt.Errorf("failed to pin %s: %w", shortenHashOrAddress(address.String()), err))
+		return
+	}
+	i.logger.Log(fmt.Sprintf("pinned %s", address.String()))
+}
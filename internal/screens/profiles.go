@@ -0,0 +1,316 @@
+package screens
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	profilesPrefKey   = "profiles"
+	profilePrefPrefix = "profile/"
+	profilesDir       = "profiles"
+	defaultProfileID  = "default"
+)
+
+var (
+	// profilePrefKeys are the preferences kept per profile, the others are shared by all profiles.
+	profilePrefKeys = []string{
+		passwordPrefKey, welcomeMessagePrefKey, swapEnablePrefKey, natAddressPrefKey, rpcEndpointPrefKey,
+		selectedStampPrefKey, batchPrefKey, uploadsPrefKey, overlayAddrPrefKey,
+		networkPrefKey, networkIDPrefKey, bootnodesPrefKey,
+	}
+	// profileSettingKeys are the settings a duplicated profile starts with. The identity is not copied.
+	profileSettingKeys = []string{
+		welcomeMessagePrefKey, swapEnablePrefKey, natAddressPrefKey, rpcEndpointPrefKey,
+		networkPrefKey, networkIDPrefKey, bootnodesPrefKey,
+	}
+)
+
+// profile is a node identity with its own data directory, password, network, mode and upload history.
+// The default profile is the node of the versions before profiles: it keeps the app datadir and the
+// preferences without prefix, so it holds the directories of the other profiles and cannot be deleted.
+type profile struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+func (p profile) dataDir(root string) string {
+	if p.ID == defaultProfileID {
+		return root
+	}
+	return filepath.Join(root, profilesDir, p.ID)
+}
+
+func (p profile) prefKey(key string) string {
+	if p.ID == defaultProfileID || !slices.Contains(profilePrefKeys, key) {
+		return key
+	}
+	return profilePrefPrefix + p.ID + "/" + key
+}
+
+func (i *index) loadProfiles() []profile {
+	var profiles []profile
+	if data := i.getPreferenceString(profilesPrefKey); data != "" {
+		if err := json.Unmarshal([]byte(data), &profiles); err != nil {
+			i.logger.Log(fmt.Sprintf("invalid profiles in preferences: %s", err.Error()))
+		}
+	}
+	if len(profiles) == 0 {
+		profiles = []profile{{ID: defaultProfileID, Name: "Default"}}
+	}
+	return profiles
+}
+
+func (i *index) saveProfiles(profiles []profile) error {
+	data, err := json.Marshal(profiles)
+	if err != nil {
+		return err
+	}
+	i.setPreference(profilesPrefKey, string(data))
+	return nil
+}
+
+// openProfile switches the app to the profile and shows its unlock view, or the setup wizard if it has no node yet.
+func (i *index) openProfile(p profile) {
+	i.profile = p
+	*i.nodeConfig = nodeConfig{isKeyStoreMem: i.nodeConfig.isKeyStoreMem}
+	if !i.nodeConfig.isKeyStoreMem {
+		i.nodeConfig.path = p.dataDir(i.rootPath)
+		if err := os.MkdirAll(i.nodeConfig.path, 0700); err != nil {
+			i.logger.Log(fmt.Sprintf("failed to create the profile datadir: %s", err.Error()))
+		}
+		i.logger.Log(fmt.Sprintf("Profile %s datadir path: %s", p.Name, i.nodeConfig.path))
+	}
+	i.openHistory()
+
+	i.nodeConfig.network = i.loadNetwork()
+	i.removeStoredPassword()
+	if i.getPreferenceString(overlayAddrPrefKey) != "" && i.keysExist() {
+		i.nodeConfig.welcomeMessage = i.getPreferenceString(welcomeMessagePrefKey)
+		i.nodeConfig.natAddress = i.getPreferenceString(natAddressPrefKey)
+		i.nodeConfig.rpcEndpoint = i.getPreferenceString(rpcEndpointPrefKey)
+		i.nodeConfig.swapEnable = i.getPreferenceBool(swapEnablePrefKey)
+
+		i.view = container.NewBorder(container.NewVBox(i.intro), nil, nil, nil, container.NewStack(i.showUnlockView()))
+	} else {
+		i.showPasswordView()
+		i.view = container.NewBorder(container.NewVBox(i.intro), nil, nil, nil, i.content)
+	}
+	i.main.Objects = []fyne.CanvasObject{i.view}
+	i.main.Refresh()
+}
+
+// profilesButton opens the profile picker. It is only shown before the node is started.
+func (i *index) profilesButton() *widget.Button {
+	button := widget.NewButtonWithIcon(fmt.Sprintf("Profile: %s", i.profile.Name), theme.AccountIcon(), i.showProfilesView)
+	if i.nodeConfig.isKeyStoreMem {
+		button.Hide()
+	}
+	return button
+}
+
+// showProfilesView lists the profiles to open one, and to create, rename, duplicate or delete them.
+func (i *index) showProfilesView() {
+	i.intro.SetText("Choose a node profile")
+	profiles := i.loadProfiles()
+	list := widget.NewList(
+		func() int {
+			return len(profiles)
+		},
+		func() fyne.CanvasObject {
+			name := widget.NewLabel("")
+			name.TextStyle.Bold = true
+			return container.NewVBox(name, widget.NewLabel(""))
+		},
+		func(id widget.ListItemID, o fyne.CanvasObject) {
+			p := profiles[id]
+			labels := o.(*fyne.Container).Objects
+			labels[0].(*widget.Label).SetText(p.Name)
+			address := i.app.Preferences().String(p.prefKey(overlayAddrPrefKey))
+			if address == "" {
+				labels[1].(*widget.Label).SetText("Not set up yet")
+			} else {
+				labels[1].(*widget.Label).SetText(shortenHashOrAddress(address))
+			}
+		},
+	)
+	reload := func() {
+		profiles = i.loadProfiles()
+		list.Refresh()
+	}
+	list.OnSelected = func(id widget.ListItemID) {
+		list.Unselect(id)
+		i.showProfileActions(profiles[id], reload)
+	}
+
+	newButton := widget.NewButtonWithIcon("New profile", theme.ContentAddIcon(), func() {
+		i.showProfileName("New profile", "", func(name string) {
+			p, err := i.createProfile(name)
+			if err != nil {
+				i.showError(err)
+				return
+			}
+			i.openProfile(p)
+		})
+	})
+	newButton.Importance = widget.HighImportance
+
+	i.view = container.NewBorder(container.NewVBox(i.intro), newButton, nil, nil, list)
+	i.main.Objects = []fyne.CanvasObject{i.view}
+	i.main.Refresh()
+}
+
+func (i *index) showProfileActions(p profile, onChanged func()) {
+	var d dialog.Dialog
+	openButton := widget.NewButton("Open", func() {
+		d.Hide()
+		i.openProfile(p)
+	})
+	openButton.Importance = widget.HighImportance
+	renameButton := widget.NewButton("Rename", func() {
+		d.Hide()
+		i.showProfileName("Rename profile", p.Name, func(name string) {
+			if err := i.renameProfile(p.ID, name); err != nil {
+				i.showError(err)
+				return
+			}
+			onChanged()
+		})
+	})
+	duplicateButton := widget.NewButton("Duplicate settings", func() {
+		d.Hide()
+		i.showProfileName("Duplicate profile", p.Name+" copy", func(name string) {
+			if _, err := i.duplicateProfile(p, name); err != nil {
+				i.showError(err)
+				return
+			}
+			onChanged()
+		})
+	})
+	deleteButton := widget.NewButton("Delete", func() {
+		d.Hide()
+		message := fmt.Sprintf("Delete %s with its keys, data and upload history?\nExport the keys first to keep the node identity.", p.Name)
+		dialog.ShowConfirm("Delete profile", message, func(ok bool) {
+			if !ok {
+				return
+			}
+			if err := i.deleteProfile(p); err != nil {
+				i.showError(err)
+				return
+			}
+			onChanged()
+		}, i.Window)
+	})
+	deleteButton.Importance = widget.DangerImportance
+	if p.ID == defaultProfileID || i.isOpenProfile(p) {
+		deleteButton.Disable()
+	}
+
+	d = dialog.NewCustom(p.Name, "Close", container.NewVBox(openButton, renameButton, duplicateButton, deleteButton), i.Window)
+	parentSize := i.Window.Canvas().Size()
+	d.Resize(fyne.NewSize(parentSize.Width*90/100, 0))
+	d.Show()
+}
+
+func (i *index) showProfileName(title, name string, onSubmit func(name string)) {
+	nameEntry := widget.NewEntry()
+	nameEntry.SetText(name)
+	nameEntry.Validator = func(s string) error {
+		if strings.TrimSpace(s) == "" {
+			return fmt.Errorf("name cannot be blank")
+		}
+		return nil
+	}
+	d := dialog.NewForm(title, "Save", "Cancel", []*widget.FormItem{{Text: "Name", Widget: nameEntry}}, func(ok bool) {
+		if ok {
+			onSubmit(strings.TrimSpace(nameEntry.Text))
+		}
+	}, i.Window)
+	parentSize := i.Window.Canvas().Size()
+	d.Resize(fyne.NewSize(parentSize.Width*90/100, 0))
+	d.Show()
+}
+
+func (i *index) createProfile(name string) (profile, error) {
+	profiles := i.loadProfiles()
+	id := make([]byte, 4)
+	if _, err := rand.Read(id); err != nil {
+		return profile{}, err
+	}
+	p := profile{ID: hex.EncodeToString(id), Name: name}
+	if err := os.MkdirAll(p.dataDir(i.rootPath), 0700); err != nil {
+		return profile{}, fmt.Errorf("failed to create the profile datadir: %w", err)
+	}
+	if err := i.saveProfiles(append(profiles, p)); err != nil {
+		return profile{}, err
+	}
+	return p, nil
+}
+
+func (i *index) renameProfile(id, name string) error {
+	profiles := i.loadProfiles()
+	for n := range profiles {
+		if profiles[n].ID == id {
+			profiles[n].Name = name
+		}
+	}
+	return i.saveProfiles(profiles)
+}
+
+// duplicateProfile creates a profile with the network, mode and node settings of p, and a new identity.
+func (i *index) duplicateProfile(p profile, name string) (profile, error) {
+	dup, err := i.createProfile(name)
+	if err != nil {
+		return profile{}, err
+	}
+	prefs := i.app.Preferences()
+	for _, key := range profileSettingKeys {
+		switch key {
+		case swapEnablePrefKey:
+			prefs.SetBool(dup.prefKey(key), prefs.Bool(p.prefKey(key)))
+		case bootnodesPrefKey:
+			prefs.SetStringList(dup.prefKey(key), prefs.StringList(p.prefKey(key)))
+		default:
+			prefs.SetString(dup.prefKey(key), prefs.String(p.prefKey(key)))
+		}
+	}
+	return dup, nil
+}
+
+// isOpenProfile reports whether p is the open profile, whose upload history holds its datadir.
+func (i *index) isOpenProfile(p profile) bool {
+	return i.history != nil && p.ID == i.profile.ID
+}
+
+// deleteProfile removes the datadir and the preferences of the profile.
+func (i *index) deleteProfile(p profile) error {
+	if p.ID == defaultProfileID {
+		return fmt.Errorf("the default profile cannot be deleted")
+	}
+	if i.isOpenProfile(p) {
+		return fmt.Errorf("the open profile cannot be deleted, open another profile first")
+	}
+	if err := os.RemoveAll(p.dataDir(i.rootPath)); err != nil {
+		return fmt.Errorf("failed to delete the profile datadir: %w", err)
+	}
+	prefs := i.app.Preferences()
+	for _, key := range profilePrefKeys {
+		prefs.RemoveValue(p.prefKey(key))
+	}
+	profiles := slices.DeleteFunc(i.loadProfiles(), func(q profile) bool {
+		return q.ID == p.ID
+	})
+	return i.saveProfiles(profiles)
+}
@@ -0,0 +1,47 @@
+package screens
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/Solar-Punk-Ltd/swarm-mobile/internal/node/nodetest"
+)
+
+func TestDeleteProfile(t *testing.T) {
+	i := newTestIndex(t, nodetest.New())
+	p, err := i.createProfile("second")
+	if err != nil {
+		t.Fatal(err)
+	}
+	i.profile = p
+	i.nodeConfig.path = p.dataDir(i.rootPath)
+	i.openHistory()
+	t.Cleanup(func() { i.history.Close() })
+
+	if err := i.deleteProfile(p); err == nil {
+		t.Fatal("the open profile was deleted")
+	}
+	if _, err := os.Stat(p.dataDir(i.rootPath)); err != nil {
+		t.Fatalf("the datadir of the open profile is gone: %v", err)
+	}
+	if err := i.deleteProfile(profile{ID: defaultProfileID}); err == nil {
+		t.Fatal("the default profile was deleted")
+	}
+
+	// once another profile is open, the profile can be deleted
+	i.profile = profile{ID: defaultProfileID, Name: "Default"}
+	i.nodeConfig.path = i.rootPath
+	i.openHistory()
+	if err := i.deleteProfile(p); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(p.dataDir(i.rootPath)); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("the datadir is not deleted: %v", err)
+	}
+	for _, q := range i.loadProfiles() {
+		if q.ID == p.ID {
+			t.Fatal("the profile is still listed")
+		}
+	}
+}
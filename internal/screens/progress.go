@@ -0,0 +1,144 @@
+package screens
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/ethersphere/bee/v2/pkg/swarm"
+)
+
+const progressRefreshInterval = 250 * time.Millisecond
+
+// countingReader counts the bytes read from the underlying reader and
+// stops reading once its context is done.
+type countingReader struct {
+	ctx context.Context
+	r   io.Reader
+	n   atomic.Int64
+}
+
+func newCountingReader(ctx context.Context, r io.Reader) *countingReader {
+	return &countingReader{ctx: ctx, r: r}
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	n, err := c.r.Read(p)
+	c.n.Add(int64(n))
+	return n, err
+}
+
+func (c *countingReader) Count() int64 {
+	return c.n.Load()
+}
+
+// transferProgress is a dialog showing the live byte count of an upload or download.
+type transferProgress struct {
+	dialog dialog.Dialog
+	bar    *widget.ProgressBar
+	label  *widget.Label
+	total  int64
+	count  func() int64
+	start  time.Time
+	stop   chan struct{}
+}
+
+// showTransferProgress shows the progress of a transfer of total bytes on w, total is -1 if unknown.
+// The cancel function is called when the user presses the Cancel button.
+func (i *index) showTransferProgress(w fyne.Window, title string, total int64, count func() int64, cancel func()) *transferProgress {
+	p := &transferProgress{
+		label: widget.NewLabel(""),
+		total: total,
+		count: count,
+		start: time.Now(),
+		stop:  make(chan struct{}),
+	}
+
+	var bar fyne.CanvasObject
+	if total > 0 {
+		p.bar = widget.NewProgressBar()
+		p.bar.Max = float64(total)
+		bar = p.bar
+	} else {
+		bar = widget.NewProgressBarInfinite()
+	}
+
+	cancelButton := widget.NewButton("Cancel", cancel)
+	cancelButton.Importance = widget.WarningImportance
+	fyne.Do(func() {
+		p.dialog = dialog.NewCustomWithoutButtons(title, container.NewVBox(bar, p.label, cancelButton), w)
+		parentSize := w.Canvas().Size()
+		p.dialog.Resize(fyne.NewSize(parentSize.Width*90/100, 0))
+		p.dialog.Show()
+	})
+
+	go func() {
+		ticker := time.NewTicker(progressRefreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				fyne.Do(p.refresh)
+			case <-p.stop:
+				return
+			}
+		}
+	}()
+
+	return p
+}
+
+func (p *transferProgress) refresh() {
+	done := p.count()
+	// the node does not report the chunks it pushed, this is the data read so far in chunks
+	chunks := (done + swarm.ChunkSize - 1) / swarm.ChunkSize
+	elapsed := time.Since(p.start)
+	throughput := float64(0)
+	if elapsed > 0 {
+		throughput = float64(done) / elapsed.Seconds()
+	}
+	stats := fmt.Sprintf("%d chunks of data read, %s/s", chunks, formatBytes(int64(throughput)))
+
+	if p.bar == nil {
+		p.label.SetText(fmt.Sprintf("%s\n%s", formatBytes(done), stats))
+		return
+	}
+
+	p.bar.SetValue(float64(done))
+	eta := "unknown"
+	if throughput > 0 {
+		remaining := time.Duration(float64(p.total-done) / throughput * float64(time.Second))
+		eta = remaining.Round(time.Second).String()
+	}
+	p.label.SetText(fmt.Sprintf("%s of %s\n%s, ETA %s", formatBytes(done), formatBytes(p.total), stats, eta))
+}
+
+func (p *transferProgress) hide() {
+	close(p.stop)
+	fyne.Do(func() {
+		p.dialog.Hide()
+	})
+}
+
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
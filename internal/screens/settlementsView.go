@@ -0,0 +1,165 @@
+package screens
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/Solar-Punk-Ltd/swarm-mobile/internal/node"
+)
+
+// settlementsBrowser lists the SWAP accounting of the node with every peer.
+type settlementsBrowser struct {
+	*index
+	window      fyne.Window
+	ctx         context.Context
+	mu          sync.Mutex
+	peers       []node.PeerSettlement
+	list        *widget.List
+	totalsLabel *widget.Label
+	emptyLabel  *widget.Label
+}
+
+func (i *index) listSettlementsButton(minSize fyne.Size) *widget.Button {
+	return widget.NewButton("Settlements", func() {
+		child, ctx := i.newChildWindow("Settlements")
+		b := &settlementsBrowser{index: i, window: child, ctx: ctx}
+		child.SetContent(b.content())
+		go b.reload()
+
+		size := child.Canvas().Content().MinSize()
+		if size.Width < minSize.Width {
+			size.Width = minSize.Width
+		}
+		if size.Height < minSize.Height {
+			size.Height = minSize.Height
+		}
+		child.Resize(size)
+		child.Show()
+	})
+}
+
+func (b *settlementsBrowser) content() fyne.CanvasObject {
+	b.list = widget.NewList(
+		func() int {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			return len(b.peers)
+		},
+		func() fyne.CanvasObject {
+			peer := widget.NewLabel("")
+			peer.TextStyle.Bold = true
+			return container.NewVBox(peer, widget.NewLabel(""))
+		},
+		func(id widget.ListItemID, o fyne.CanvasObject) {
+			peer := b.peer(id)
+			labels := o.(*fyne.Container).Objects
+			labels[0].(*widget.Label).SetText(shortenHashOrAddress(peer.Peer))
+			labels[1].(*widget.Label).SetText(fmt.Sprintf("balance %s, received %s, sent %s", formatBZZ(peer.Balance), formatBZZ(peer.Received), formatBZZ(peer.Sent)))
+		},
+	)
+	b.list.OnSelected = func(id widget.ListItemID) {
+		b.list.Unselect(id)
+		b.showDetails(b.peer(id))
+	}
+
+	b.totalsLabel = widget.NewLabel("Loading...")
+	b.emptyLabel = widget.NewLabel("No settlements with peers")
+	b.emptyLabel.Hide()
+	refreshButton := widget.NewButtonWithIcon("Refresh", theme.ViewRefreshIcon(), func() {
+		go b.reload()
+	})
+
+	return container.NewBorder(b.totalsLabel, container.NewVBox(b.emptyLabel, refreshButton), nil, nil, b.list)
+}
+
+func (b *settlementsBrowser) peer(id widget.ListItemID) node.PeerSettlement {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.peers[id]
+}
+
+func (b *settlementsBrowser) reload() {
+	settlements, err := b.node.Settlements(b.ctx)
+	if err != nil {
+		if b.ctx.Err() == nil {
+			b.showErrorIn(b.window, fmt.Errorf("failed to get the settlements: %w", err))
+		}
+		return
+	}
+
+	b.mu.Lock()
+	b.peers = settlements.Peers
+	b.mu.Unlock()
+	totals := fmt.Sprintf("Balance: %s\nReceived: %s, sent: %s\nUncashed: %s",
+		formatBZZ(settlements.TotalBalance), formatBZZ(settlements.TotalReceived),
+		formatBZZ(settlements.TotalSent), formatBZZ(settlements.TotalUncashed))
+	fyne.Do(func() {
+		b.totalsLabel.SetText(totals)
+		if len(settlements.Peers) == 0 {
+			b.emptyLabel.Show()
+		} else {
+			b.emptyLabel.Hide()
+		}
+		b.list.Refresh()
+	})
+}
+
+func (b *settlementsBrowser) showDetails(peer node.PeerSettlement) {
+	lastCheque := func(payout *big.Int) string {
+		if payout == nil {
+			return "none"
+		}
+		return formatBZZ(payout)
+	}
+	details := widget.NewForm(
+		widget.NewFormItem("Peer", widget.NewLabel(shortenHashOrAddress(peer.Peer))),
+		widget.NewFormItem("Balance", widget.NewLabel(formatBZZ(peer.Balance))),
+		widget.NewFormItem("Received", widget.NewLabel(formatBZZ(peer.Received))),
+		widget.NewFormItem("Sent", widget.NewLabel(formatBZZ(peer.Sent))),
+		widget.NewFormItem("Last received cheque", widget.NewLabel(lastCheque(peer.LastReceivedCheque))),
+		widget.NewFormItem("Last sent cheque", widget.NewLabel(lastCheque(peer.LastSentCheque))),
+		widget.NewFormItem("Uncashed", widget.NewLabel(formatBZZ(peer.Uncashed))),
+	)
+
+	var d dialog.Dialog
+	copyButton := widget.NewButtonWithIcon("Copy peer", theme.ContentCopyIcon(), func() {
+		b.window.Clipboard().SetContent(peer.Peer)
+	})
+	cashOutButton := widget.NewButtonWithIcon("Cash out", theme.DownloadIcon(), func() {
+		dialog.ShowConfirm("Cash out", fmt.Sprintf("Cash out %s received from %s?", formatBZZ(peer.Uncashed), shortenHashOrAddress(peer.Peer)), func(ok bool) {
+			if !ok {
+				return
+			}
+			d.Hide()
+			go b.cashOut(peer)
+		}, b.window)
+	})
+	cashOutButton.Importance = widget.HighImportance
+	if peer.Uncashed.Sign() <= 0 {
+		cashOutButton.Disable()
+	}
+
+	d = dialog.NewCustom(shortenHashOrAddress(peer.Peer), "Close", container.NewVBox(details, copyButton, cashOutButton), b.window)
+	parentSize := b.window.Canvas().Size()
+	d.Resize(fyne.NewSize(parentSize.Width*90/100, 0))
+	d.Show()
+}
+
+func (b *settlementsBrowser) cashOut(peer node.PeerSettlement) {
+	hash, err := b.node.CashOut(b.ctx, peer.Peer)
+	if err != nil {
+		b.showErrorIn(b.window, err)
+		return
+	}
+	b.logger.Log(fmt.Sprintf("cash out of %s from %s: transaction %s", formatBZZ(peer.Uncashed), peer.Peer, hash.String()))
+	b.waitForTransaction(b.window, "Cash out transaction", hash)
+	b.reload()
+}
@@ -1,33 +1,26 @@
 package screens
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"time"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/data/binding"
 	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
 	"fyne.io/fyne/v2/widget"
-	"github.com/ethersphere/bee/v2/pkg/swarm"
-)
 
-type uploadedItem struct {
-	Name      string
-	Reference string
-	Size      int64
-	Timestamp time.Time
-	Mimetype  string
-}
+	"github.com/Solar-Punk-Ltd/swarm-mobile/internal/history"
+)
 
 func (i *index) showUploadCard() *widget.Card {
 	upForm := i.uploadForm()
-	listButton := i.listUploadsButton(fyne.NewSize(200, 100))
-	return widget.NewCard("Upload", "upload content into swarm", container.NewVBox(upForm, listButton))
+	folderButton := i.uploadFolderButton()
+	listButton := i.listUploadsButton(fyne.NewSize(350, 450))
+	return widget.NewCard("Upload", "upload content into swarm", container.NewVBox(upForm, folderButton, listButton))
 }
 
 func (i *index) uploadForm() *widget.Form {
@@ -38,7 +31,7 @@ func (i *index) uploadForm() *widget.Form {
 	path := widget.NewEntry()
 	path.Bind(pathBind)
 	path.Disable()
-	var file io.Reader
+	var fileURI fyne.URI
 	openFileButton := widget.NewButton("File Open", func() {
 		fd := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
 			if err != nil {
@@ -48,126 +41,121 @@ func (i *index) uploadForm() *widget.Form {
 			if reader == nil {
 				return
 			}
-			defer reader.Close()
-			data, err := io.ReadAll(reader)
-			if err != nil {
-				i.showError(err)
-				return
-			}
-			fileSize = int64(len(data))
+			// the file is reopened on submit and streamed into swarm
+			reader.Close()
+			fileSize = uriSize(reader.URI())
 			mimetype = reader.URI().MimeType()
 			err = pathBind.Set(reader.URI().Name())
 			if err != nil {
 				i.showError(err)
 				return
 			}
-			file = bytes.NewReader(data)
+			fileURI = reader.URI()
 		}, i.Window)
 		fd.Show()
 	})
 
+	encryptCheck := widget.NewCheck("Encrypt", nil)
+	pinCheck := widget.NewCheck("Pin", nil)
+
 	upForm := &widget.Form{
 		Items: []*widget.FormItem{
 			{Text: "Add file", Widget: path, HintText: "Filepath"},
 			{Text: "Choose File", Widget: openFileButton},
+			{Text: "", Widget: encryptCheck, HintText: "Only holders of the reference can read the content"},
+			{Text: "", Widget: pinCheck, HintText: "Keep a copy of the content on this node"},
 		},
 	}
 	upForm.OnSubmit = func() {
+		if fileURI == nil {
+			i.showError(fmt.Errorf("please select a file"))
+			return
+		}
+		// the selection is taken on the UI thread, the form is cleared for the next file
+		uri, filename, mime, size := fileURI, path.Text, mimetype, fileSize
+		encrypt, pin := encryptCheck.Checked, pinCheck.Checked
+		fileURI = nil
+		if err := pathBind.Set(""); err != nil {
+			i.logger.Log(fmt.Sprintf("failed to bind path: %s", err.Error()))
+		}
+		upForm.Disable()
 		go func() {
-			defer func() {
-				err := pathBind.Set("")
-				if err != nil {
-					i.logger.Log(fmt.Sprintf("failed to bind path: %s", err.Error()))
-				}
-				file = nil
-			}()
-			if file == nil {
-				i.showError(fmt.Errorf("please select a file"))
-				return
-			}
-			batchID := i.getPreferenceString(batchPrefKey)
-			if batchID == "" {
-				i.showError(fmt.Errorf("please select a batch of stamp"))
-				return
-			}
-			filename := path.Text
-			i.logger.Log(fmt.Sprintf("stamp selected: %s", batchID))
-			i.showProgressWithMessage(fmt.Sprintf("Uploading %s", filename))
-			ref, _, err := i.bl.AddFileBzz(context.Background(), batchID, filename, mimetype, false, swarm.ZeroAddress, false, 0, file)
-			if err != nil {
-				i.hideProgress()
-				i.showError(err)
-				return
-			}
-			i.logger.Log(fmt.Sprintf("reference of the uploaded file: %s", ref.String()))
-			uploadedSrt := i.getPreferenceString(uploadsPrefKey)
-			uploads := []uploadedItem{}
-			if uploadedSrt != "" {
-				err := json.Unmarshal([]byte(uploadedSrt), &uploads)
-				if err != nil {
-					i.showError(err)
-				}
-			}
-			uploads = append(uploads, uploadedItem{
-				Name:      filename,
-				Reference: ref.String(),
-				Timestamp: time.Now(),
-				Size:      fileSize,
-				Mimetype:  mimetype,
-			})
-			data, err := json.Marshal(uploads)
-			if err != nil {
-				i.hideProgress()
-				i.showError(err)
-				return
-			}
-			i.setPreference(uploadsPrefKey, string(data))
-			d := dialog.NewCustomConfirm("Upload successful", "Ok", "Cancel", i.copyDialog(shortenHashOrAddress(ref.String()), ref.String()), func(b bool) {}, i.Window)
-			i.hideProgress()
-			d.Show()
+			defer fyne.Do(upForm.Enable)
+			i.uploadFile(uri, filename, mime, size, encrypt, pin)
 		}()
 	}
 
 	return upForm
 }
 
-func (i *index) listUploadsButton(minSize fyne.Size) *widget.Button {
-	button := widget.NewButton("All Uploads", func() {
-		uploadedContent := container.NewVBox()
-		uploadedContentWrapper := container.NewScroll(uploadedContent)
-		uploadedSrt := i.getPreferenceString(uploadsPrefKey)
-		uploads := []uploadedItem{}
-		if uploadedSrt != "" {
-			err := json.Unmarshal([]byte(uploadedSrt), &uploads)
-			if err != nil {
-				i.showError(err)
-			}
-			for _, v := range uploads {
-				ref := v.Reference
-				name := v.Name
-				label := widget.NewLabel(fmt.Sprintf("%s\n%s", name, shortenHashOrAddress(ref)))
-				label.Wrapping = fyne.TextWrapWord
-				item := container.NewBorder(label, nil, nil, i.copyButton(ref))
-				uploadedContent.Add(item)
-			}
-		}
-
-		if len(uploads) == 0 {
-			uploadedContent.Add(widget.NewLabel("Empty upload list"))
-		}
-
-		child := i.app.NewWindow("Uploaded content")
-		size := child.Canvas().Content().Size()
-		if size.Width < minSize.Width {
-			size.Width = minSize.Width
+// uploadFile streams the file at uri into swarm with the selected batch, size is -1 if unknown.
+func (i *index) uploadFile(uri fyne.URI, filename, mimetype string, size int64, encrypt, pin bool) {
+	batchID := i.getPreferenceString(batchPrefKey)
+	if batchID == "" {
+		i.showError(fmt.Errorf("please select a batch of stamp"))
+		return
+	}
+	i.logger.Log(fmt.Sprintf("stamp selected: %s", batchID))
+	if size < 0 {
+		// content URIs have no size metadata, read the file once to show the progress
+		var err error
+		if size, err = readSize(uri); err != nil {
+			i.logger.Log(fmt.Sprintf("failed to read the size of %s: %s", filename, err.Error()))
+			size = -1
 		}
-		if size.Height < minSize.Height {
-			size.Height = minSize.Height
+	}
+	file, err := storage.Reader(uri)
+	if err != nil {
+		i.showError(fmt.Errorf("failed to open %s: %w", filename, err))
+		return
+	}
+	defer file.Close()
+	ctx, cancel := context.WithCancel(i.ctx)
+	defer cancel()
+	counter := newCountingReader(ctx, file)
+	progress := i.showTransferProgress(i.Window, fmt.Sprintf("Uploading %s", filename), size, counter.Count, cancel)
+	ref, err := i.node.AddFileBzz(ctx, batchID, filename, mimetype, encrypt, counter)
+	progress.hide()
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			i.logger.Log(fmt.Sprintf("upload of %s cancelled", filename))
+			return
 		}
-		child.Resize(size)
-		child.SetContent(uploadedContentWrapper)
-		child.Show()
+		i.showError(err)
+		return
+	}
+	if size < 0 {
+		size = counter.Count()
+	}
+	i.logger.Log(fmt.Sprintf("reference of the uploaded file: %s", ref.String()))
+	err = i.addUpload(history.Item{
+		Name:      filename,
+		Reference: ref.String(),
+		Timestamp: time.Now(),
+		Size:      size,
+		Mimetype:  mimetype,
+		BatchID:   batchID,
 	})
+	if err != nil {
+		i.showError(err)
+		return
+	}
+	i.showUploadSuccess(ref.String())
+	if pin {
+		i.pin(ref)
+	}
+}
 
-	return button
+func (i *index) addUpload(item history.Item) error {
+	if i.history == nil {
+		return fmt.Errorf("upload history is not available")
+	}
+	return i.history.Add(item)
+}
+
+func (i *index) showUploadSuccess(ref string) {
+	fyne.Do(func() {
+		d := dialog.NewCustomConfirm("Upload successful", "Ok", "Cancel", i.copyDialog(shortenHashOrAddress(ref), ref), func(b bool) {}, i.Window)
+		d.Show()
+	})
 }
@@ -0,0 +1,36 @@
+package screens
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"fyne.io/fyne/v2/storage"
+
+	"github.com/Solar-Punk-Ltd/swarm-mobile/internal/node/nodetest"
+)
+
+// TestUploadFileUnknownSize uploads a file without size metadata, as picked from a content URI.
+func TestUploadFileUnknownSize(t *testing.T) {
+	n := nodetest.New()
+	i := newTestIndex(t, n)
+	i.openHistory()
+	t.Cleanup(func() { i.history.Close() })
+	i.setPreference(batchPrefKey, "ff")
+	path := filepath.Join(t.TempDir(), "a.txt")
+	if err := os.WriteFile(path, []byte("hello swarm"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	i.uploadFile(storage.NewFileURI(path), "a.txt", "text/plain", -1, false, false)
+	if len(n.Files) != 1 {
+		t.Fatalf("got %d uploads, want 1", len(n.Files))
+	}
+	items, err := i.history.List(0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || items[0].Name != "a.txt" || items[0].Size != 11 || items[0].Mimetype != "text/plain" {
+		t.Errorf("got history %+v", items)
+	}
+}
@@ -0,0 +1,204 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/Solar-Punk-Ltd/swarm-mobile/internal/history"
+	"github.com/Solar-Punk-Ltd/swarm-mobile/internal/node"
+)
+
+const (
+	uploadsPageSize = 50
+	sortByDate      = "Newest"
+	sortByName      = "Name"
+	sortBySize      = "Size"
+)
+
+var sortOrders = map[string]history.Order{
+	sortByDate: history.ByDate,
+	sortByName: history.ByName,
+	sortBySize: history.BySize,
+}
+
+// uploadsBrowser lists the upload history with search, sorting and per item actions.
+type uploadsBrowser struct {
+	*index
+	window     fyne.Window
+	items      []history.Item
+	query      string
+	order      history.Order
+	list       *widget.List
+	moreButton *widget.Button
+	emptyLabel *widget.Label
+}
+
+func (i *index) listUploadsButton(minSize fyne.Size) *widget.Button {
+	button := widget.NewButton("All Uploads", func() {
+		child, _ := i.newChildWindow("Uploaded content")
+		b := &uploadsBrowser{index: i, window: child}
+		child.SetContent(b.content())
+		b.reload()
+
+		size := child.Canvas().Content().MinSize()
+		if size.Width < minSize.Width {
+			size.Width = minSize.Width
+		}
+		if size.Height < minSize.Height {
+			size.Height = minSize.Height
+		}
+		child.Resize(size)
+		child.Show()
+	})
+
+	return button
+}
+
+func (b *uploadsBrowser) content() fyne.CanvasObject {
+	searchEntry := widget.NewEntry()
+	searchEntry.SetPlaceHolder("Search name or reference")
+	searchEntry.OnChanged = func(s string) {
+		b.query = s
+		b.reload()
+	}
+
+	sortSelect := widget.NewSelect([]string{sortByDate, sortByName, sortBySize}, func(s string) {
+		b.order = sortOrders[s]
+		b.reload()
+	})
+	sortSelect.SetSelected(sortByDate)
+
+	b.list = widget.NewList(
+		func() int {
+			return len(b.items)
+		},
+		func() fyne.CanvasObject {
+			name := widget.NewLabel("")
+			name.Truncation = fyne.TextTruncateEllipsis
+			name.TextStyle.Bold = true
+			return container.NewVBox(name, widget.NewLabel(""))
+		},
+		func(id widget.ListItemID, o fyne.CanvasObject) {
+			item := b.items[id]
+			labels := o.(*fyne.Container).Objects
+			labels[0].(*widget.Label).SetText(item.Name)
+			labels[1].(*widget.Label).SetText(fmt.Sprintf("%s, %s", formatBytes(item.Size), item.Timestamp.Format("2006-01-02 15:04")))
+		},
+	)
+	b.list.OnSelected = func(id widget.ListItemID) {
+		b.list.Unselect(id)
+		b.showDetails(b.items[id])
+	}
+
+	b.moreButton = widget.NewButton("Load more", b.loadPage)
+	b.moreButton.Hide()
+	b.emptyLabel = widget.NewLabel("Empty upload list")
+	b.emptyLabel.Hide()
+
+	top := container.NewBorder(nil, nil, nil, sortSelect, searchEntry)
+	return container.NewBorder(top, container.NewVBox(b.emptyLabel, b.moreButton), nil, nil, b.list)
+}
+
+func (b *uploadsBrowser) reload() {
+	b.items = nil
+	b.loadPage()
+}
+
+func (b *uploadsBrowser) loadPage() {
+	if b.history == nil {
+		return
+	}
+	items, err := b.history.Search(b.query, b.order, len(b.items), uploadsPageSize)
+	if err != nil {
+		b.showErrorIn(b.window, err)
+		return
+	}
+	b.items = append(b.items, items...)
+
+	if len(items) == uploadsPageSize {
+		b.moreButton.Show()
+	} else {
+		b.moreButton.Hide()
+	}
+	if len(b.items) == 0 {
+		b.emptyLabel.Show()
+	} else {
+		b.emptyLabel.Hide()
+	}
+	b.list.Refresh()
+}
+
+func (b *uploadsBrowser) showDetails(item history.Item) {
+	batch := item.BatchID
+	if batch == "" {
+		batch = "unknown"
+	} else {
+		batch = shortenHashOrAddress(batch)
+	}
+	details := widget.NewForm(
+		widget.NewFormItem("Reference", widget.NewLabel(shortenHashOrAddress(item.Reference))),
+		widget.NewFormItem("Size", widget.NewLabel(formatBytes(item.Size))),
+		widget.NewFormItem("Type", widget.NewLabel(itemType(item))),
+		widget.NewFormItem("Uploaded", widget.NewLabel(item.Timestamp.Format("2006-01-02 15:04:05"))),
+		widget.NewFormItem("Batch", widget.NewLabel(batch)),
+		widget.NewFormItem("Encrypted", widget.NewLabel(fmt.Sprintf("%t", isEncryptedReference(item.Reference)))),
+	)
+
+	var d dialog.Dialog
+	copyButton := widget.NewButtonWithIcon("Copy reference", theme.ContentCopyIcon(), func() {
+		b.window.Clipboard().SetContent(item.Reference)
+	})
+	shareButton := widget.NewButtonWithIcon("Copy share link", theme.MailForwardIcon(), func() {
+		b.window.Clipboard().SetContent(shareLink(b.nodeConfig.network, item.Reference))
+	})
+	if b.nodeConfig.network.Gateway == "" {
+		shareButton.Hide()
+	}
+	downloadButton := widget.NewButtonWithIcon("Download", theme.DownloadIcon(), func() {
+		addr, err := parseReference(item.Reference)
+		if err != nil {
+			b.showErrorIn(b.window, err)
+			return
+		}
+		d.Hide()
+		go b.download(b.window, addr)
+	})
+	removeButton := widget.NewButtonWithIcon("Remove from history", theme.DeleteIcon(), func() {
+		dialog.ShowConfirm("Remove upload", fmt.Sprintf("Remove %s from the upload history?\nThe content stays on swarm.", item.Name), func(ok bool) {
+			if !ok {
+				return
+			}
+			if err := b.history.Delete(item.Reference); err != nil {
+				b.showErrorIn(b.window, err)
+				return
+			}
+			d.Hide()
+			b.reload()
+		}, b.window)
+	})
+	removeButton.Importance = widget.DangerImportance
+
+	content := container.NewVBox(details, copyButton, shareButton, downloadButton, removeButton)
+	d = dialog.NewCustom(item.Name, "Close", content, b.window)
+	parentSize := b.window.Canvas().Size()
+	d.Resize(fyne.NewSize(parentSize.Width*90/100, 0))
+	d.Show()
+}
+
+// shareLink returns the URL of the content on the gateway of the network.
+func shareLink(network node.Network, reference string) string {
+	return strings.TrimSuffix(network.Gateway, "/") + "/bzz/" + reference + "/"
+}
+
+func itemType(item history.Item) string {
+	if item.IsManifest() {
+		return "Folder"
+	}
+	return item.Mimetype
+}
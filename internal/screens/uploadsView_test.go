@@ -0,0 +1,21 @@
+package screens
+
+import (
+	"testing"
+
+	"github.com/Solar-Punk-Ltd/swarm-mobile/internal/node"
+)
+
+func TestShareLink(t *testing.T) {
+	ref := "a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2"
+	if got, want := shareLink(node.Mainnet, ref), "https://api.gateway.ethswarm.org/bzz/"+ref+"/"; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+	network := node.Network{Gateway: "http://localhost:1633/"}
+	if got, want := shareLink(network, ref), "http://localhost:1633/bzz/"+ref+"/"; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+	if node.Testnet.Gateway != "" {
+		t.Error("testnet content is shared on a gateway")
+	}
+}
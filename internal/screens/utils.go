@@ -1,9 +1,15 @@
 package screens
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"math/big"
+	"os"
 	"runtime/debug"
+	"slices"
+	"strings"
+	"time"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
@@ -36,6 +42,29 @@ func (i *index) readAppData(filePath string) (string, error) {
 	return string(data), nil
 }
 
+// uriSize returns the size of the file behind the uri from its metadata, or -1 if the size is not known.
+// Only local files have it, the content URIs of the Android and iOS pickers are sized with readSize.
+func uriSize(uri fyne.URI) int64 {
+	if uri.Scheme() != "file" {
+		return -1
+	}
+	info, err := os.Stat(uri.Path())
+	if err != nil {
+		return -1
+	}
+	return info.Size()
+}
+
+// readSize returns the size of the file behind the uri by reading it through.
+func readSize(uri fyne.URI) (int64, error) {
+	reader, err := storage.Reader(uri)
+	if err != nil {
+		return 0, err
+	}
+	defer reader.Close()
+	return io.Copy(io.Discard, reader)
+}
+
 func (i *index) printAppInfo() {
 	info, ok := debug.ReadBuildInfo()
 	if !ok {
@@ -72,8 +101,13 @@ func (i *index) printAppInfo() {
 }
 
 func (i *index) showProgressWithMessage(message string) {
+	i.showProgressIn(i.Window, message)
+}
+
+// showProgressIn shows the progress dialog on w, e.g. a child window.
+func (i *index) showProgressIn(w fyne.Window, message string) {
 	fyne.Do(func() {
-		i.progress = dialog.NewCustomWithoutButtons(message, widget.NewProgressBarInfinite(), i)
+		i.progress = dialog.NewCustomWithoutButtons(message, widget.NewProgressBarInfinite(), w)
 		i.progress.Show()
 	})
 }
@@ -87,11 +121,16 @@ func (i *index) hideProgress() {
 }
 
 func (i *index) showError(err error) {
+	i.showErrorIn(i.Window, err)
+}
+
+// showErrorIn shows the error on w, e.g. a child window.
+func (i *index) showErrorIn(w fyne.Window, err error) {
 	fyne.Do(func() {
 		label := widget.NewLabel(err.Error())
 		label.Wrapping = fyne.TextWrapWord
-		d := dialog.NewCustom("Error", "       Close       ", label, i.Window)
-		parentSize := i.Window.Canvas().Size()
+		d := dialog.NewCustom("Error", "       Close       ", label, w)
+		parentSize := w.Canvas().Size()
 		d.Resize(fyne.NewSize(parentSize.Width*90/100, 0))
 		d.Show()
 	})
@@ -120,20 +159,65 @@ func shortenHashOrAddress(item string) string {
 	return fmt.Sprintf("%s[...]%s", item[0:6], item[len(item)-6:])
 }
 
+// formatBZZ formats an amount of PLUR, the smallest unit of xBZZ, as xBZZ.
+func formatBZZ(plur *big.Int) string {
+	bzz := new(big.Float).Quo(new(big.Float).SetInt(plur), big.NewFloat(plurPerBZZ))
+	return fmt.Sprintf("%s %s", bzz.Text('f', 4), SwarmTokenSymbol)
+}
+
+// parseBZZ parses a decimal amount of xBZZ into PLUR.
+func parseBZZ(s string) (*big.Int, error) {
+	whole, fraction, _ := strings.Cut(strings.TrimSpace(s), ".")
+	if len(fraction) > bzzDecimals {
+		return nil, fmt.Errorf("%s has at most %d decimals", SwarmTokenSymbol, bzzDecimals)
+	}
+	plur, ok := new(big.Int).SetString(whole+fraction+strings.Repeat("0", bzzDecimals-len(fraction)), 10)
+	if !ok || plur.Sign() <= 0 {
+		return nil, fmt.Errorf("invalid amount %q", s)
+	}
+	return plur, nil
+}
+
+// formatNativeToken formats an amount of wei as xDAI.
+func formatNativeToken(wei *big.Int) string {
+	xdai := new(big.Float).Quo(new(big.Float).SetInt(wei), big.NewFloat(weiPerNativeToken))
+	return fmt.Sprintf("%s %s", xdai.Text('f', 4), NativeTokenSymbol)
+}
+
+// formatTTL formats a time to live in days and hours.
+func formatTTL(ttl time.Duration) string {
+	if ttl <= 0 {
+		return "expired"
+	}
+	days := ttl / (24 * time.Hour)
+	hours := (ttl % (24 * time.Hour)) / time.Hour
+	if days == 0 {
+		return fmt.Sprintf("%dh", hours)
+	}
+	return fmt.Sprintf("%dd %dh", days, hours)
+}
+
 func (i *index) copyDialog(info, data string) fyne.CanvasObject {
 	return container.NewStack(container.NewBorder(nil, nil, nil, i.copyButton(data), widget.NewLabel(info)))
 }
 
 func (i *index) getPreferenceString(key string) string {
 	if !i.nodeConfig.isKeyStoreMem {
-		return i.app.Preferences().String(key)
+		return i.app.Preferences().String(i.profile.prefKey(key))
 	}
 	return ""
 }
 
+func (i *index) getPreferenceStringList(key string) []string {
+	if !i.nodeConfig.isKeyStoreMem {
+		return i.app.Preferences().StringList(i.profile.prefKey(key))
+	}
+	return nil
+}
+
 func (i *index) getPreferenceBool(key string) bool {
 	if !i.nodeConfig.isKeyStoreMem {
-		return i.app.Preferences().Bool(key)
+		return i.app.Preferences().Bool(i.profile.prefKey(key))
 	}
 	return false
 }
@@ -142,24 +226,46 @@ func (i *index) setPreference(key string, value interface{}) {
 	if !i.nodeConfig.isKeyStoreMem {
 		switch valueType := value.(type) {
 		case string:
-			i.app.Preferences().SetString(key, valueType)
+			i.app.Preferences().SetString(i.profile.prefKey(key), valueType)
 		case []string:
-			i.app.Preferences().SetStringList(key, valueType)
+			i.app.Preferences().SetStringList(i.profile.prefKey(key), valueType)
 		case bool:
-			i.app.Preferences().SetBool(key, valueType)
+			i.app.Preferences().SetBool(i.profile.prefKey(key), valueType)
 		case []bool:
-			i.app.Preferences().SetBoolList(key, valueType)
+			i.app.Preferences().SetBoolList(i.profile.prefKey(key), valueType)
 		case int:
-			i.app.Preferences().SetInt(key, valueType)
+			i.app.Preferences().SetInt(i.profile.prefKey(key), valueType)
 		case []int:
-			i.app.Preferences().SetIntList(key, valueType)
+			i.app.Preferences().SetIntList(i.profile.prefKey(key), valueType)
 		case float64:
-			i.app.Preferences().SetFloat(key, valueType)
+			i.app.Preferences().SetFloat(i.profile.prefKey(key), valueType)
 		case []float64:
-			i.app.Preferences().SetFloatList(key, valueType)
+			i.app.Preferences().SetFloatList(i.profile.prefKey(key), valueType)
 		case nil:
 		default:
 			i.logger.Log(fmt.Sprintf("Invalid type for preference: %T", value))
 		}
 	}
 }
+
+// newChildWindow creates a window for a secondary screen, with a context cancelled when the window is closed.
+// The app lock closes the child windows that are still open.
+func (i *index) newChildWindow(title string) (fyne.Window, context.Context) {
+	child := i.app.NewWindow(title)
+	ctx, cancel := context.WithCancel(i.ctx)
+	i.childWindows = append(i.childWindows, child)
+	child.SetOnClosed(func() {
+		cancel()
+		i.childWindows = slices.DeleteFunc(i.childWindows, func(w fyne.Window) bool {
+			return w == child
+		})
+	})
+	return child, ctx
+}
+
+func (i *index) closeChildWindows() {
+	for _, w := range slices.Clone(i.childWindows) {
+		w.Close()
+	}
+	i.childWindows = nil
+}
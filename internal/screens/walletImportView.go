@@ -0,0 +1,195 @@
+package screens
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"io"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/Solar-Punk-Ltd/swarm-mobile/internal/node"
+)
+
+const (
+	walletSourceHex      = "Private key"
+	walletSourceKeystore = "Keystore JSON"
+	walletSourceMnemonic = "Mnemonic"
+)
+
+// walletSource holds the inputs of the import existing wallet form.
+type walletSource struct {
+	kind             string
+	hexKey           string
+	keystoreJSON     string
+	keystorePassword string
+	mnemonic         string
+	path             string
+}
+
+// key reads the private key from the selected source.
+func (s walletSource) key() (*ecdsa.PrivateKey, error) {
+	switch s.kind {
+	case walletSourceHex:
+		return node.KeyFromHex(s.hexKey)
+	case walletSourceKeystore:
+		return node.KeyFromKeystore([]byte(s.keystoreJSON), s.keystorePassword)
+	case walletSourceMnemonic:
+		return node.KeyFromMnemonic(s.mnemonic, s.path)
+	default:
+		return nil, fmt.Errorf("select the wallet to import")
+	}
+}
+
+// importWalletButton lets the user start the node with an existing Ethereum key instead of a new one.
+// The key is encrypted with the password of passwordEntry, which is passed to onImported.
+func (i *index) importWalletButton(passwordEntry *widget.Entry, onImported func(password string)) *widget.Button {
+	button := widget.NewButton("Import existing wallet", func() {
+		if passwordEntry.Text == "" {
+			i.showError(fmt.Errorf("set the node password first, the imported key is encrypted with it"))
+			return
+		}
+		i.showImportWallet(passwordEntry.Text, onImported)
+	})
+	if i.nodeConfig.isKeyStoreMem {
+		button.Hide()
+	}
+	return button
+}
+
+func (i *index) showImportWallet(password string, onImported func(password string)) {
+	hexEntry := widget.NewPasswordEntry()
+	hexEntry.SetPlaceHolder("0x...")
+	hexForm := widget.NewForm(widget.NewFormItem("Private key", hexEntry))
+
+	keystoreEntry := widget.NewMultiLineEntry()
+	keystoreEntry.SetPlaceHolder(`{"address": ..., "crypto": ..., "version": 3}`)
+	keystorePasswordEntry := widget.NewPasswordEntry()
+	openButton := widget.NewButtonWithIcon("Open file", theme.FolderOpenIcon(), func() {
+		fd := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil {
+				i.showError(err)
+				return
+			}
+			if reader == nil {
+				return
+			}
+			defer reader.Close()
+			data, err := io.ReadAll(reader)
+			if err != nil {
+				i.showError(fmt.Errorf("failed to read keystore: %w", err))
+				return
+			}
+			keystoreEntry.SetText(string(data))
+		}, i.Window)
+		fd.Show()
+	})
+	keystoreForm := container.NewVBox(widget.NewForm(
+		widget.NewFormItem("Keystore", keystoreEntry),
+		widget.NewFormItem("Password", keystorePasswordEntry),
+	), openButton)
+
+	mnemonicEntry := widget.NewMultiLineEntry()
+	mnemonicEntry.Wrapping = fyne.TextWrapWord
+	mnemonicEntry.SetPlaceHolder("12 or 24 words")
+	pathEntry := widget.NewEntry()
+	pathEntry.SetText(node.DefaultDerivationPath)
+	mnemonicForm := widget.NewForm(
+		widget.NewFormItem("Mnemonic", mnemonicEntry),
+		widget.NewFormItem("Derivation path", pathEntry),
+	)
+
+	forms := map[string]fyne.CanvasObject{
+		walletSourceHex:      hexForm,
+		walletSourceKeystore: keystoreForm,
+		walletSourceMnemonic: mnemonicForm,
+	}
+	sourceSelect := widget.NewSelect([]string{walletSourceHex, walletSourceKeystore, walletSourceMnemonic}, func(s string) {
+		for kind, form := range forms {
+			if kind == s {
+				form.Show()
+			} else {
+				form.Hide()
+			}
+		}
+	})
+	sourceSelect.SetSelected(walletSourceHex)
+
+	content := container.NewVBox(sourceSelect, hexForm, keystoreForm, mnemonicForm)
+	d := dialog.NewCustomConfirm("Import existing wallet", "Next", "Cancel", content, func(ok bool) {
+		if !ok {
+			return
+		}
+		source := walletSource{
+			kind:             sourceSelect.Selected,
+			hexKey:           hexEntry.Text,
+			keystoreJSON:     keystoreEntry.Text,
+			keystorePassword: keystorePasswordEntry.Text,
+			mnemonic:         mnemonicEntry.Text,
+			path:             pathEntry.Text,
+		}
+		go i.confirmImportWallet(source, password, onImported)
+	}, i.Window)
+	parentSize := i.Window.Canvas().Size()
+	d.Resize(fyne.NewSize(parentSize.Width*90/100, 0))
+	d.Show()
+}
+
+// confirmImportWallet shows the address of the key and writes it into the keystore once the user confirms it.
+func (i *index) confirmImportWallet(source walletSource, password string, onImported func(password string)) {
+	i.showProgressWithMessage("Reading the key")
+	key, err := source.key()
+	i.hideProgress()
+	if err != nil {
+		i.showError(err)
+		return
+	}
+	address := crypto.PubkeyToAddress(key.PublicKey)
+
+	exists, err := node.KeysExist(i.nodeConfig.path)
+	if err != nil {
+		i.showError(err)
+		return
+	}
+	message := fmt.Sprintf("The node will use the address\n%s\nIs this the wallet to import?", address.Hex())
+	if exists {
+		message += "\nThe existing node keys on this device will be replaced."
+	}
+
+	fyne.Do(func() {
+		dialog.ShowConfirm("Confirm wallet", message, func(ok bool) {
+			if !ok {
+				return
+			}
+			go i.writeWalletKey(key, password, onImported)
+		}, i.Window)
+	})
+}
+
+func (i *index) writeWalletKey(key *ecdsa.PrivateKey, password string, onImported func(password string)) {
+	i.showProgressWithMessage("Importing the wallet")
+	address, err := node.WriteSwarmKey(i.nodeConfig.path, key, password)
+	i.hideProgress()
+	if errors.Is(err, node.ErrNodeStateExists) {
+		i.confirmResetNodeState(func() {
+			i.writeWalletKey(key, password, onImported)
+		})
+		return
+	}
+	if err != nil {
+		i.showError(fmt.Errorf("failed to import the wallet: %w", err))
+		return
+	}
+	i.logger.Log(fmt.Sprintf("Imported wallet %s", address.Hex()))
+	i.setPreference(overlayAddrPrefKey, address.Hex())
+	fyne.Do(func() {
+		i.showImportedAddress(address)
+		onImported(password)
+	})
+}
@@ -0,0 +1,160 @@
+package screens
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/Solar-Punk-Ltd/swarm-mobile/internal/node"
+)
+
+const (
+	weiPerNativeToken     = 1e18
+	walletRefreshInterval = 30 * time.Second
+	walletQueryTimeout    = 15 * time.Second
+)
+
+// walletPanel shows the xDAI and xBZZ balances of the node address, read from the RPC endpoint
+// so that they are known before the node is started.
+type walletPanel struct {
+	*index
+	ctx         context.Context
+	address     common.Address
+	nativeLabel *widget.Label
+	bzzLabel    *widget.Label
+	statusLabel *widget.Label
+	mu          sync.Mutex
+	balances    *node.Balances
+	// onBalances is called on the UI goroutine with whether the balances are enough for light mode.
+	onBalances func(sufficient bool)
+}
+
+// newWalletPanel returns the panel of address, refreshed until ctx is done. onBalances may be nil.
+func (i *index) newWalletPanel(ctx context.Context, address common.Address, onBalances func(sufficient bool)) (*walletPanel, fyne.CanvasObject) {
+	w := &walletPanel{
+		index:       i,
+		ctx:         ctx,
+		address:     address,
+		onBalances:  onBalances,
+		nativeLabel: widget.NewLabel(fmt.Sprintf("%s: ...", NativeTokenSymbol)),
+		bzzLabel:    widget.NewLabel(fmt.Sprintf("%s: ...", SwarmTokenSymbol)),
+		statusLabel: widget.NewLabel(""),
+	}
+	w.statusLabel.Wrapping = fyne.TextWrapWord
+	w.statusLabel.Hide()
+
+	refreshButton := widget.NewButtonWithIcon("", theme.ViewRefreshIcon(), func() {
+		go w.refresh()
+	})
+	go w.refreshLoop()
+
+	header := container.NewBorder(nil, nil, widget.NewLabel("Wallet balance:"), refreshButton)
+	return w, container.NewVBox(header, w.nativeLabel, w.bzzLabel, w.statusLabel)
+}
+
+func (w *walletPanel) refreshLoop() {
+	w.refresh()
+	ticker := time.NewTicker(walletRefreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			w.refresh()
+		case <-w.ctx.Done():
+			return
+		}
+	}
+}
+
+// settings reads the RPC endpoint and whether the network is mainnet on the UI goroutine, where
+// they are edited. ok is false if the panel was stopped first.
+func (w *walletPanel) settings() (rpcEndpoint string, mainnet, ok bool) {
+	type settings struct {
+		rpcEndpoint string
+		mainnet     bool
+	}
+	read := make(chan settings, 1)
+	fyne.Do(func() {
+		read <- settings{w.nodeConfig.rpcEndpoint, w.nodeConfig.network.Mainnet}
+	})
+	select {
+	case s := <-read:
+		return s.rpcEndpoint, s.mainnet, true
+	case <-w.ctx.Done():
+		return "", false, false
+	}
+}
+
+func (w *walletPanel) refresh() {
+	rpcEndpoint, mainnet, ok := w.settings()
+	if !ok {
+		return
+	}
+	if rpcEndpoint == "" {
+		if !mainnet {
+			w.setStatus("Set an RPC endpoint to see the wallet balance")
+			return
+		}
+		rpcEndpoint = defaultRPC
+	}
+
+	ctx, cancel := context.WithTimeout(w.ctx, walletQueryTimeout)
+	defer cancel()
+	balances, err := node.QueryBalances(ctx, rpcEndpoint, w.address)
+	if err != nil {
+		if w.ctx.Err() == nil {
+			w.logger.Log(fmt.Sprintf("failed to get the wallet balance: %s", err.Error()))
+			w.setStatus("Cannot get the wallet balance")
+		}
+		return
+	}
+
+	w.mu.Lock()
+	w.balances = &balances
+	w.mu.Unlock()
+	fyne.Do(func() {
+		w.nativeLabel.SetText(fmt.Sprintf("%s: %s", NativeTokenSymbol, formatNativeToken(balances.NativeToken)))
+		w.bzzLabel.SetText(fmt.Sprintf("%s: %s", SwarmTokenSymbol, formatBZZ(balances.BZZ)))
+		if w.onBalances != nil {
+			w.onBalances(balances.Sufficient())
+		}
+	})
+	if balances.Sufficient() {
+		w.setStatus("")
+		return
+	}
+	w.setStatus(fmt.Sprintf("Light mode needs at least %s (for gas) and %s", formatNativeToken(node.MinNativeTokenBalance), formatBZZ(node.MinBZZBalance)))
+}
+
+func (w *walletPanel) setStatus(status string) {
+	fyne.Do(func() {
+		w.statusLabel.SetText(status)
+		if status == "" {
+			w.statusLabel.Hide()
+		} else {
+			w.statusLabel.Show()
+		}
+	})
+}
+
+// sufficient reports whether the last known balances are enough to start in light mode.
+func (w *walletPanel) sufficient() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.balances == nil {
+		return fmt.Errorf("the wallet balance of %s is not known yet", shortenHashOrAddress(w.address.String()))
+	}
+	if !w.balances.Sufficient() {
+		return fmt.Errorf("cannot continue in light-mode until there is at least %s (for gas) and %s available on %s",
+			formatNativeToken(node.MinNativeTokenBalance), formatBZZ(node.MinBZZBalance), shortenHashOrAddress(w.address.String()))
+	}
+	return nil
+}
@@ -1,14 +1,26 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"os"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/app"
+	"github.com/Solar-Punk-Ltd/swarm-mobile/internal/daemon"
 	"github.com/Solar-Punk-Ltd/swarm-mobile/internal/screens"
 )
 
 func main() {
+	headless := flag.Bool("headless", false, "run the node without GUI")
+	daemonFlags := daemon.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+
+	if *headless {
+		runHeadless(daemonFlags)
+		return
+	}
+
 	a := app.NewWithID("com.solarpunk.swarmmobile")
 
 	w := a.NewWindow("Swarm Mobile")
@@ -21,6 +33,19 @@ func main() {
 	tidyUp("Window Closed")
 }
 
+func runHeadless(flags *daemon.Flags) {
+	config, err := flags.Config()
+	if err != nil {
+		tidyUp(err.Error())
+		os.Exit(1)
+	}
+	if err := daemon.Run(config); err != nil {
+		tidyUp(err.Error())
+		os.Exit(1)
+	}
+	tidyUp("Interrupted")
+}
+
 func tidyUp(msg string) {
 	fmt.Printf("Swarm Mobile Exited: %s\n", msg)
 }